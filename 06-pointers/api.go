@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// A REST API for wallets
+//
+//	POST /wallets                 {"id": "adam"}                           create a wallet
+//	GET  /wallets/{id}                                                     get the balance
+//	POST /wallets/{id}/deposit    {"amount": 10}                           deposit
+//	POST /wallets/{id}/withdraw   {"amount": 10}                           withdraw
+//	POST /transfers               {"from": "a", "to": "b", "amount": 10}   move money between wallets
+//
+// A client that times out can't tell if its request worked, so it will retry - which could
+// withdraw the money twice. Mutating requests can send an `Idempotency-Key` header: the first response
+// for a key is remembered and sent back again for any retry with the same key, without doing the work twice.
+//
+// Remembering every key forever would use more and more memory, so only the most recent
+// `maxSavedResponses` keys are kept. Retries usually come soon after the first request, but one that
+// arrives after its key has been forgotten is treated as a new request and done again.
+//
+// Like `Server` in the context chapter, each handler passes `r.Context()` on and gives up if the request is
+// cancelled. Unlike that chapter's `Store.Fetch`, WalletStore doesn't take the context, so cancelling stops at
+// the store: the operations check `ctx.Err()` up until they call it, and never after. That's on purpose -
+//   - an `Append` that has started writing has to finish: a Sync can't be interrupted part way, and
+//     stopping after the write but before returning would save an entry the wallet in memory doesn't have
+//   - a transfer is two Appends, and giving up between them would mean undoing the first one
+//
+// So a request is either cancelled before anything is saved (and nothing is written back), or it runs to the end.
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// maxSavedResponses is how many idempotency keys a WalletServer remembers
+const maxSavedResponses = 10_000
+
+var (
+	ErrInvalidAmount       = errors.New("amount must be greater than zero")
+	ErrInvalidRequest      = errors.New("request body is not valid")
+	ErrIdempotencyKeyReuse = errors.New("idempotency key was already used for a different request")
+)
+
+type WalletServer struct {
+	store WalletStore
+	// mu is held for every change, so a transfer can't be seen half done
+	// and two retries with the same idempotency key can't both run
+	mu        sync.Mutex
+	wallets   map[string]*StoredWallet
+	responses map[string]savedResponse
+	// responseKeys are the keys in responses, oldest first, so we know which one to forget
+	responseKeys []string
+	maxResponses int
+	handler      http.Handler
+}
+
+type savedResponse struct {
+	request string
+	status  int
+	body    []byte
+}
+
+type walletRequest struct {
+	ID     string  `json:"id"`
+	Amount Bitcoin `json:"amount"`
+}
+
+type transferRequest struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Amount Bitcoin `json:"amount"`
+}
+
+type walletResponse struct {
+	ID      string  `json:"id"`
+	Balance Bitcoin `json:"balance"`
+}
+
+type transferResponse struct {
+	From walletResponse `json:"from"`
+	To   walletResponse `json:"to"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func NewWalletServer(store WalletStore) *WalletServer {
+	s := &WalletServer{
+		store:        store,
+		wallets:      make(map[string]*StoredWallet),
+		responses:    make(map[string]savedResponse),
+		maxResponses: maxSavedResponses,
+	}
+
+	// since Go 1.22 the ServeMux patterns can include the method and wildcards like {id}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /wallets", s.mutation(http.StatusCreated, s.create))
+	mux.HandleFunc("GET /wallets/{id}", s.balance)
+	mux.HandleFunc("POST /wallets/{id}/deposit", s.mutation(http.StatusOK, s.deposit))
+	mux.HandleFunc("POST /wallets/{id}/withdraw", s.mutation(http.StatusOK, s.withdraw))
+	mux.HandleFunc("POST /transfers", s.mutation(http.StatusOK, s.transfer))
+	s.handler = mux
+
+	return s
+}
+
+func (s *WalletServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+func (s *WalletServer) balance(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wallet, err := s.wallet(r.Context(), r.PathValue("id"))
+	if isCancelled(err) {
+		return
+	}
+	if err != nil {
+		writeJSON(w, statusFor(err), errorResponse{err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, walletResponse{wallet.ID(), wallet.Balance()})
+}
+
+// an operation does the work for a mutating request, the returned value is sent back as JSON
+type operation func(ctx context.Context, r *http.Request, body []byte) (any, error)
+
+// mutation turns an operation into a handler, taking care of locking and idempotency keys
+func (s *WalletServer) mutation(successStatus int, op operation) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{ErrInvalidRequest.Error()})
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		key := r.Header.Get(idempotencyKeyHeader)
+		request := r.Method + " " + r.URL.Path + " " + string(body)
+
+		if saved, ok := s.responses[key]; key != "" && ok {
+			if saved.request != request {
+				writeJSON(w, http.StatusUnprocessableEntity, errorResponse{ErrIdempotencyKeyReuse.Error()})
+				return
+			}
+			writeRaw(w, saved.status, saved.body)
+			return
+		}
+
+		result, err := op(r.Context(), r, body)
+
+		// just like `Server` in the context chapter, if the request was cancelled we don't write anything.
+		// Nothing is saved either, so a retry with the same key will try again.
+		if isCancelled(err) {
+			return
+		}
+
+		status := successStatus
+		if err != nil {
+			status, result = statusFor(err), errorResponse{err.Error()}
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if key != "" {
+			s.saveResponse(key, savedResponse{request, status, data})
+		}
+		writeRaw(w, status, data)
+	}
+}
+
+// saveResponse remembers the response for key, forgetting the oldest key if there are too many. s.mu must be held.
+func (s *WalletServer) saveResponse(key string, response savedResponse) {
+	s.responses[key] = response
+	s.responseKeys = append(s.responseKeys, key)
+
+	for len(s.responseKeys) > s.maxResponses {
+		delete(s.responses, s.responseKeys[0])
+		s.responseKeys = s.responseKeys[1:]
+	}
+}
+
+func (s *WalletServer) create(ctx context.Context, _ *http.Request, body []byte) (any, error) {
+	var req walletRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.ID == "" {
+		return nil, ErrInvalidRequest
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.wallet(ctx, req.ID); err == nil {
+		return nil, ErrWalletExists
+	} else if err != ErrWalletNotFound {
+		return nil, err
+	}
+
+	wallet, err := OpenWallet(s.store, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.wallets[req.ID] = wallet
+	return walletResponse{wallet.ID(), wallet.Balance()}, nil
+}
+
+func (s *WalletServer) deposit(ctx context.Context, r *http.Request, body []byte) (any, error) {
+	wallet, amount, err := s.walletAndAmount(ctx, r, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := wallet.Deposit(amount); err != nil {
+		return nil, err
+	}
+	return walletResponse{wallet.ID(), wallet.Balance()}, nil
+}
+
+func (s *WalletServer) withdraw(ctx context.Context, r *http.Request, body []byte) (any, error) {
+	wallet, amount, err := s.walletAndAmount(ctx, r, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := wallet.Withdraw(amount); err != nil {
+		return nil, err
+	}
+	return walletResponse{wallet.ID(), wallet.Balance()}, nil
+}
+
+func (s *WalletServer) transfer(ctx context.Context, _ *http.Request, body []byte) (any, error) {
+	var req transferRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.From == "" || req.To == "" || req.From == req.To {
+		return nil, ErrInvalidRequest
+	}
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	from, err := s.wallet(ctx, req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.wallet(ctx, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	// last chance to give up - once the money has left `from` we finish the transfer
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := from.Withdraw(req.Amount); err != nil {
+		return nil, err
+	}
+	if err := to.Deposit(req.Amount); err != nil {
+		// put the money back, so it doesn't disappear
+		if refundErr := from.Deposit(req.Amount); refundErr != nil {
+			return nil, errors.Join(err, refundErr)
+		}
+		return nil, err
+	}
+
+	return transferResponse{
+		From: walletResponse{from.ID(), from.Balance()},
+		To:   walletResponse{to.ID(), to.Balance()},
+	}, nil
+}
+
+func (s *WalletServer) walletAndAmount(ctx context.Context, r *http.Request, body []byte) (*StoredWallet, Bitcoin, error) {
+	var req walletRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, 0, ErrInvalidRequest
+	}
+	if req.Amount <= 0 {
+		return nil, 0, ErrInvalidAmount
+	}
+
+	wallet, err := s.wallet(ctx, r.PathValue("id"))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	return wallet, req.Amount, nil
+}
+
+// wallet finds an open wallet, loading it from the store the first time it's asked for.
+// s.mu must be held.
+func (s *WalletServer) wallet(ctx context.Context, id string) (*StoredWallet, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if wallet, ok := s.wallets[id]; ok {
+		return wallet, nil
+	}
+
+	// check it exists first, as OpenWallet would create it
+	if _, err := s.store.Balance(id); err != nil {
+		return nil, err
+	}
+
+	wallet, err := OpenWallet(s.store, id)
+	if err != nil {
+		return nil, err
+	}
+	s.wallets[id] = wallet
+	return wallet, nil
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, ErrWalletNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrWalletExists):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidRequest), errors.Is(err, ErrInvalidAmount):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrInsufficientFunds),
+		errors.Is(err, ErrOverdraftLimitExceeded),
+		errors.Is(err, ErrDailyLimitExceeded),
+		errors.Is(err, ErrBelowMinimumBalance):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func isCancelled(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeRaw(w, status, data)
+}
+
+func writeRaw(w http.ResponseWriter, status int, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
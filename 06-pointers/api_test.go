@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWalletServer(t *testing.T) {
+	t.Run("create a wallet and get its balance", func(t *testing.T) {
+		server := NewWalletServer(NewInMemoryWalletStore())
+
+		response := serve(server, http.MethodPost, "/wallets", `{"id": "adam"}`, "")
+		assertStatus(t, response, http.StatusCreated)
+
+		response = serve(server, http.MethodGet, "/wallets/adam", "", "")
+		assertStatus(t, response, http.StatusOK)
+		assertWalletResponse(t, response, walletResponse{"adam", 0})
+	})
+
+	t.Run("create a wallet twice", func(t *testing.T) {
+		server := newTestWalletServer(t, "adam")
+
+		response := serve(server, http.MethodPost, "/wallets", `{"id": "adam"}`, "")
+
+		assertStatus(t, response, http.StatusConflict)
+	})
+
+	t.Run("unknown wallet", func(t *testing.T) {
+		server := NewWalletServer(NewInMemoryWalletStore())
+
+		response := serve(server, http.MethodGet, "/wallets/nobody", "", "")
+
+		assertStatus(t, response, http.StatusNotFound)
+	})
+
+	t.Run("deposit and withdraw", func(t *testing.T) {
+		server := newTestWalletServer(t, "adam")
+
+		response := serve(server, http.MethodPost, "/wallets/adam/deposit", `{"amount": 20}`, "")
+		assertStatus(t, response, http.StatusOK)
+		assertWalletResponse(t, response, walletResponse{"adam", 20})
+
+		response = serve(server, http.MethodPost, "/wallets/adam/withdraw", `{"amount": 5}`, "")
+		assertStatus(t, response, http.StatusOK)
+		assertWalletResponse(t, response, walletResponse{"adam", 15})
+	})
+
+	t.Run("withdraw insufficient funds", func(t *testing.T) {
+		server := newTestWalletServer(t, "adam")
+
+		response := serve(server, http.MethodPost, "/wallets/adam/withdraw", `{"amount": 5}`, "")
+
+		assertStatus(t, response, http.StatusUnprocessableEntity)
+		assertBody(t, response, `{"error":"cannot withdraw, insufficient funds"}`)
+	})
+
+	t.Run("invalid amount", func(t *testing.T) {
+		server := newTestWalletServer(t, "adam")
+
+		response := serve(server, http.MethodPost, "/wallets/adam/deposit", `{"amount": -5}`, "")
+
+		assertStatus(t, response, http.StatusBadRequest)
+	})
+
+	t.Run("transfer", func(t *testing.T) {
+		server := newTestWalletServer(t, "adam", "chris")
+		serve(server, http.MethodPost, "/wallets/adam/deposit", `{"amount": 20}`, "")
+
+		response := serve(server, http.MethodPost, "/transfers", `{"from": "adam", "to": "chris", "amount": 15}`, "")
+
+		assertStatus(t, response, http.StatusOK)
+		assertBody(t, response, `{"from":{"id":"adam","balance":5},"to":{"id":"chris","balance":15}}`)
+	})
+
+	t.Run("transfer insufficient funds leaves both wallets alone", func(t *testing.T) {
+		server := newTestWalletServer(t, "adam", "chris")
+
+		response := serve(server, http.MethodPost, "/transfers", `{"from": "adam", "to": "chris", "amount": 15}`, "")
+		assertStatus(t, response, http.StatusUnprocessableEntity)
+
+		assertWalletResponse(t, serve(server, http.MethodGet, "/wallets/adam", "", ""), walletResponse{"adam", 0})
+		assertWalletResponse(t, serve(server, http.MethodGet, "/wallets/chris", "", ""), walletResponse{"chris", 0})
+	})
+
+	t.Run("retrying with an idempotency key doesn't deposit twice", func(t *testing.T) {
+		server := newTestWalletServer(t, "adam")
+
+		first := serve(server, http.MethodPost, "/wallets/adam/deposit", `{"amount": 20}`, "key-1")
+		retry := serve(server, http.MethodPost, "/wallets/adam/deposit", `{"amount": 20}`, "key-1")
+
+		assertStatus(t, retry, first.Code)
+		assertBody(t, retry, first.Body.String())
+		assertWalletResponse(t, serve(server, http.MethodGet, "/wallets/adam", "", ""), walletResponse{"adam", 20})
+	})
+
+	t.Run("reusing an idempotency key for a different request", func(t *testing.T) {
+		server := newTestWalletServer(t, "adam")
+
+		serve(server, http.MethodPost, "/wallets/adam/deposit", `{"amount": 20}`, "key-1")
+		response := serve(server, http.MethodPost, "/wallets/adam/deposit", `{"amount": 30}`, "key-1")
+
+		assertStatus(t, response, http.StatusUnprocessableEntity)
+		assertWalletResponse(t, serve(server, http.MethodGet, "/wallets/adam", "", ""), walletResponse{"adam", 20})
+	})
+
+	t.Run("only the most recent idempotency keys are remembered", func(t *testing.T) {
+		server := newTestWalletServer(t, "adam")
+		server.maxResponses = 2
+
+		for _, key := range []string{"key-1", "key-2", "key-3"} {
+			serve(server, http.MethodPost, "/wallets/adam/deposit", `{"amount": 10}`, key)
+		}
+		if len(server.responses) != 2 {
+			t.Errorf("got %d saved responses want 2", len(server.responses))
+		}
+
+		// key-3 is still remembered, so retrying it does nothing
+		serve(server, http.MethodPost, "/wallets/adam/deposit", `{"amount": 10}`, "key-3")
+		assertWalletResponse(t, serve(server, http.MethodGet, "/wallets/adam", "", ""), walletResponse{"adam", 30})
+
+		// key-1 was forgotten, so it's treated as a new request
+		serve(server, http.MethodPost, "/wallets/adam/deposit", `{"amount": 10}`, "key-1")
+		assertWalletResponse(t, serve(server, http.MethodGet, "/wallets/adam", "", ""), walletResponse{"adam", 40})
+	})
+
+	t.Run("does nothing if the request is cancelled", func(t *testing.T) {
+		server := newTestWalletServer(t, "adam")
+
+		request := newWalletRequest(http.MethodPost, "/wallets/adam/deposit", `{"amount": 20}`, "key-1")
+		ctx, cancel := context.WithCancel(request.Context())
+		cancel()
+		request = request.WithContext(ctx)
+
+		response := &SpyResponseWriter{}
+		server.ServeHTTP(response, request)
+
+		if response.written {
+			t.Error("a response should not have been written")
+		}
+
+		// the cancelled request wasn't remembered, so the retry does the deposit
+		retry := serve(server, http.MethodPost, "/wallets/adam/deposit", `{"amount": 20}`, "key-1")
+		assertWalletResponse(t, retry, walletResponse{"adam", 20})
+	})
+}
+
+func newTestWalletServer(t testing.TB, ids ...string) *WalletServer {
+	t.Helper()
+	store := NewInMemoryWalletStore()
+	for _, id := range ids {
+		_, err := OpenWallet(store, id)
+		assertNoError(t, err)
+	}
+	return NewWalletServer(store)
+}
+
+func newWalletRequest(method, path, body, idempotencyKey string) *http.Request {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	if idempotencyKey != "" {
+		request.Header.Set(idempotencyKeyHeader, idempotencyKey)
+	}
+	return request
+}
+
+func serve(server http.Handler, method, path, body, idempotencyKey string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, newWalletRequest(method, path, body, idempotencyKey))
+	return response
+}
+
+// the same spy as in the context chapter, so we can tell nothing at all was written
+type SpyResponseWriter struct {
+	written bool
+}
+
+func (s *SpyResponseWriter) Header() http.Header {
+	s.written = true
+	return nil
+}
+
+func (s *SpyResponseWriter) Write([]byte) (int, error) {
+	s.written = true
+	return 0, nil
+}
+
+func (s *SpyResponseWriter) WriteHeader(statusCode int) {
+	s.written = true
+}
+
+func assertStatus(t testing.TB, response *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if response.Code != want {
+		t.Errorf("got status %d want %d, body %s", response.Code, want, response.Body)
+	}
+}
+
+func assertBody(t testing.TB, response *httptest.ResponseRecorder, want string) {
+	t.Helper()
+	if got := response.Body.String(); got != want {
+		t.Errorf("got body %s want %s", got, want)
+	}
+}
+
+func assertWalletResponse(t testing.TB, response *httptest.ResponseRecorder, want walletResponse) {
+	t.Helper()
+	var got walletResponse
+	if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+		t.Fatalf("could not decode response %q: %v", response.Body, err)
+	}
+	if got != want {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
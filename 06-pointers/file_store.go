@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileWalletStore saves wallets to a directory so they survive a restart.
+//
+// Every entry is appended as one JSON line to a "journal" file and flushed to disk (`Sync`) before
+// Append returns, so a successful Append is never lost. Replaying a journal that grows forever gets slow,
+// so every `snapshotEvery` entries the whole state is written to a "snapshot" file and the journal starts again.
+//
+// If the program crashes half way through writing a line, the journal ends with a partial record.
+// That entry was never acknowledged to the caller, so on start-up we simply cut it off.
+type FileWalletStore struct {
+	mu            sync.Mutex
+	dir           string
+	journal       *os.File
+	ledgers       ledgers
+	seq           uint64
+	sinceSnapshot int
+	snapshotEvery int
+}
+
+const (
+	journalFile  = "journal.log"
+	snapshotFile = "snapshot.json"
+)
+
+var ErrCorruptJournal = errors.New("wallet journal is corrupt")
+
+// each journal line has a sequence number, so after a crash between writing a snapshot
+// and emptying the journal we know which entries are already in the snapshot
+type journalRecord struct {
+	Seq   uint64      `json:"seq"`
+	Entry LedgerEntry `json:"entry"`
+}
+
+type snapshot struct {
+	Seq     uint64  `json:"seq"`
+	Wallets ledgers `json:"wallets"`
+}
+
+func OpenFileWalletStore(dir string, snapshotEvery int) (*FileWalletStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &FileWalletStore{dir: dir, ledgers: ledgers{}, snapshotEvery: snapshotEvery}
+
+	if err := s.loadSnapshot(); err != nil {
+		return nil, err
+	}
+
+	journal, err := os.OpenFile(filepath.Join(dir, journalFile), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	// if the journal was just created it's only on disk once the directory is synced,
+	// otherwise a crash could lose the whole file along with the entries Append said were saved
+	if err := syncDir(dir); err != nil {
+		journal.Close()
+		return nil, err
+	}
+
+	if err := s.replay(journal); err != nil {
+		journal.Close()
+		return nil, err
+	}
+
+	s.journal = journal
+	return s, nil
+}
+
+func (s *FileWalletStore) loadSnapshot() error {
+	data, err := os.ReadFile(filepath.Join(s.dir, snapshotFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("reading snapshot: %w", err)
+	}
+
+	if snap.Wallets != nil {
+		s.ledgers = snap.Wallets
+	}
+	s.seq = snap.Seq
+	return nil
+}
+
+// replay applies every complete record in the journal, and cuts off a partial last one
+func (s *FileWalletStore) replay(journal *os.File) error {
+	reader := bufio.NewReader(journal)
+	var offset int64
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// no newline at the end means the last write never finished (or the file is empty)
+			if len(line) > 0 {
+				return s.truncateJournal(journal, offset)
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		var record journalRecord
+		if err := json.Unmarshal(bytes.TrimSpace(line), &record); err != nil {
+			// a bad line is only safe to drop if nothing was written after it
+			if _, peekErr := reader.Peek(1); peekErr == io.EOF {
+				return s.truncateJournal(journal, offset)
+			}
+			return fmt.Errorf("%w: record at byte %d: %v", ErrCorruptJournal, offset, err)
+		}
+
+		offset += int64(len(line))
+
+		if record.Seq <= s.seq {
+			continue // already in the snapshot
+		}
+		if err := s.ledgers.apply(record.Entry); err != nil {
+			return fmt.Errorf("%w: record %d: %v", ErrCorruptJournal, record.Seq, err)
+		}
+		s.seq = record.Seq
+		s.sinceSnapshot++
+	}
+
+	_, err := journal.Seek(offset, io.SeekStart)
+	return err
+}
+
+func (s *FileWalletStore) truncateJournal(journal *os.File, offset int64) error {
+	if err := journal.Truncate(offset); err != nil {
+		return err
+	}
+	if err := journal.Sync(); err != nil {
+		return err
+	}
+	_, err := journal.Seek(offset, io.SeekStart)
+	return err
+}
+
+func (s *FileWalletStore) Append(entry LedgerEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ledgers.check(entry); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(journalRecord{Seq: s.seq + 1, Entry: entry})
+	if err != nil {
+		return err
+	}
+
+	offset, err := s.journal.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	// write the whole line in one call then flush it to disk before we say it's saved.
+	// If either fails, cut the journal back so the next entry isn't written after a partial one.
+	if _, err := s.journal.Write(append(line, '\n')); err != nil {
+		s.truncateJournal(s.journal, offset)
+		return err
+	}
+	if err := s.journal.Sync(); err != nil {
+		s.truncateJournal(s.journal, offset)
+		return err
+	}
+
+	s.seq++
+	s.sinceSnapshot++
+	if err := s.ledgers.apply(entry); err != nil {
+		return err
+	}
+
+	// the entry is already safe in the journal, so if the snapshot fails
+	// we don't report the Append as failed - we'll just try again after the next entry
+	if s.snapshotEvery > 0 && s.sinceSnapshot >= s.snapshotEvery {
+		s.snapshot() //nolint:errcheck
+	}
+	return nil
+}
+
+func (s *FileWalletStore) Balance(id string) (Bitcoin, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ledgers.balance(id)
+}
+
+func (s *FileWalletStore) Ledger(id string) ([]LedgerEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ledgers.ledger(id)
+}
+
+// Snapshot writes the current state and empties the journal.
+// It happens automatically every `snapshotEvery` entries, but can be called at any time.
+func (s *FileWalletStore) Snapshot() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshot()
+}
+
+func (s *FileWalletStore) snapshot() error {
+	data, err := json.Marshal(snapshot{Seq: s.seq, Wallets: s.ledgers})
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file and rename it, so there is never a half-written snapshot on disk -
+	// a rename either happens completely or not at all.
+	tmp := filepath.Join(s.dir, snapshotFile+".tmp")
+	if err := writeFileSync(tmp, data); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, snapshotFile)); err != nil {
+		return err
+	}
+	// the rename is only a change to the directory, which has its own Sync. Without it the truncate below
+	// could reach the disk before the rename does, and a crash would lose the journal and keep the old snapshot.
+	if err := syncDir(s.dir); err != nil {
+		return err
+	}
+
+	// if we crash here the journal still has entries that are in the snapshot,
+	// but their sequence numbers mean they are skipped when replaying.
+	if err := s.truncateJournal(s.journal, 0); err != nil {
+		return err
+	}
+	s.sinceSnapshot = 0
+	return nil
+}
+
+func writeFileSync(name string, data []byte) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// syncDir flushes a directory's entries (which files it has, and their names) to disk
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil {
+		d.Close()
+		return err
+	}
+	return d.Close()
+}
+
+func (s *FileWalletStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.journal.Close()
+}
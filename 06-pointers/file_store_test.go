@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileWalletStore(t *testing.T) {
+	t.Run("survives a restart", func(t *testing.T) {
+		dir := t.TempDir()
+		store := openTestFileStore(t, dir, 0)
+		appendEntries(t, store, testEntry("adam", EntryOpened, 0), testEntry("adam", EntryDeposit, 20))
+		store.Close()
+
+		reopened := openTestFileStore(t, dir, 0)
+
+		assertStoredBalance(t, reopened, "adam", Bitcoin(20))
+	})
+
+	t.Run("survives a restart after a snapshot", func(t *testing.T) {
+		dir := t.TempDir()
+		store := openTestFileStore(t, dir, 2)
+		appendEntries(t, store,
+			testEntry("adam", EntryOpened, 0),
+			testEntry("adam", EntryDeposit, 20),
+			testEntry("adam", EntryWithdrawal, 5),
+		)
+		store.Close()
+
+		// the first two entries went into the snapshot, leaving one in the journal
+		if lines := journalLines(t, dir); lines != 1 {
+			t.Errorf("got %d journal lines want 1", lines)
+		}
+
+		reopened := openTestFileStore(t, dir, 2)
+
+		assertStoredBalance(t, reopened, "adam", Bitcoin(15))
+		assertLedger(t, reopened, "adam", []LedgerEntry{
+			testEntry("adam", EntryOpened, 0),
+			testEntry("adam", EntryDeposit, 20),
+			testEntry("adam", EntryWithdrawal, 5),
+		})
+	})
+
+	t.Run("recovers from a truncated final write", func(t *testing.T) {
+		dir := t.TempDir()
+		store := openTestFileStore(t, dir, 0)
+		appendEntries(t, store, testEntry("adam", EntryOpened, 0), testEntry("adam", EntryDeposit, 20))
+		store.Close()
+
+		// simulate a crash half way through writing another entry
+		appendToJournal(t, dir, `{"seq":3,"entry":{"wallet":"adam","kind":"depo`)
+
+		reopened := openTestFileStore(t, dir, 0)
+		assertStoredBalance(t, reopened, "adam", Bitcoin(20))
+
+		// the partial record has gone, so new entries are written on a clean line
+		appendEntries(t, reopened, testEntry("adam", EntryDeposit, 1))
+		reopened.Close()
+
+		assertStoredBalance(t, openTestFileStore(t, dir, 0), "adam", Bitcoin(21))
+	})
+
+	t.Run("does not replay entries already in the snapshot", func(t *testing.T) {
+		dir := t.TempDir()
+		store := openTestFileStore(t, dir, 0)
+		appendEntries(t, store, testEntry("adam", EntryOpened, 0), testEntry("adam", EntryDeposit, 20))
+
+		journal, err := os.ReadFile(filepath.Join(dir, journalFile))
+		assertNoError(t, err)
+		assertNoError(t, store.Snapshot())
+		store.Close()
+
+		// simulate a crash after the snapshot was written but before the journal was emptied
+		appendToJournal(t, dir, string(journal))
+
+		assertStoredBalance(t, openTestFileStore(t, dir, 0), "adam", Bitcoin(20))
+	})
+
+	t.Run("refuses a journal corrupted in the middle", func(t *testing.T) {
+		dir := t.TempDir()
+		appendToJournal(t, dir, "not json\n"+`{"seq":1,"entry":{"wallet":"adam","kind":"opened"}}`+"\n")
+
+		_, err := OpenFileWalletStore(dir, 0)
+
+		if !errors.Is(err, ErrCorruptJournal) {
+			t.Errorf("got %v want %v", err, ErrCorruptJournal)
+		}
+	})
+}
+
+func openTestFileStore(t *testing.T, dir string, snapshotEvery int) *FileWalletStore {
+	t.Helper()
+	store, err := OpenFileWalletStore(dir, snapshotEvery)
+	if err != nil {
+		t.Fatalf("could not open store: %v", err)
+	}
+	// closing twice just returns an error we don't care about
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func appendEntries(t testing.TB, store WalletStore, entries ...LedgerEntry) {
+	t.Helper()
+	for _, entry := range entries {
+		assertNoError(t, store.Append(entry))
+	}
+}
+
+func appendToJournal(t testing.TB, dir, data string) {
+	t.Helper()
+	f, err := os.OpenFile(filepath.Join(dir, journalFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	assertNoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString(data)
+	assertNoError(t, err)
+}
+
+func journalLines(t testing.TB, dir string) int {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, journalFile))
+	assertNoError(t, err)
+	lines := 0
+	for _, b := range data {
+		if b == '\n' {
+			lines++
+		}
+	}
+	return lines
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Generalising the wallet to hold more than one currency.
+// `Bitcoin` is fine when there is only one currency, but once there are several
+// an amount on its own is meaningless - we need to know *which* currency it is in.
+
+// Currency is an ISO style currency code e.g. "GBP", "USD" or "BTC"
+type Currency string
+
+// Money pairs an amount (in the smallest unit of the currency, e.g. pence) with its currency.
+// Using whole units avoids the rounding problems you get when storing money as a float.
+type Money struct {
+	Amount   int
+	Currency Currency
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+}
+
+var (
+	ErrCurrencyMismatch = errors.New("cannot combine amounts in different currencies without converting")
+	ErrNoExchangeRate   = errors.New("no exchange rate for currency pair")
+	ErrNegativeAmount   = errors.New("amount must not be negative")
+)
+
+// Add only works on amounts of the same currency - adding 10 GBP to 10 USD is not 20 of anything,
+// so the caller has to convert one of them first.
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{m.Amount + other.Amount, m.Currency}, nil
+}
+
+// RateProvider is the dependency that knows exchange rates.
+// It is an interface so tests can use a fixed table, and real code can fetch live rates.
+type RateProvider interface {
+	Rate(from, to Currency) (float64, error)
+}
+
+// CurrencyPair is used as the key for a rate table - structs can be map keys as long as all their fields are comparable.
+type CurrencyPair struct {
+	From, To Currency
+}
+
+// StaticRates is a fixed table of exchange rates, handy for tests.
+// A rate is how many units of `To` you get for one unit of `From`.
+type StaticRates map[CurrencyPair]float64
+
+func (s StaticRates) Rate(from, to Currency) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	if rate, ok := s[CurrencyPair{from, to}]; ok {
+		return rate, nil
+	}
+
+	// if we only know the rate the other way round we can still work it out
+	if rate, ok := s[CurrencyPair{to, from}]; ok && rate != 0 {
+		return 1 / rate, nil
+	}
+
+	return 0, fmt.Errorf("%w: %s to %s", ErrNoExchangeRate, from, to)
+}
+
+// Convert turns money into another currency using the rates, rounding to the nearest whole unit.
+func Convert(m Money, to Currency, rates RateProvider) (Money, error) {
+	rate, err := rates.Rate(m.Currency, to)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{int(math.Round(float64(m.Amount) * rate)), to}, nil
+}
+
+// MultiWallet keeps a separate balance for each currency.
+type MultiWallet struct {
+	balances map[Currency]int
+	rates    RateProvider
+}
+
+// a constructor is used so that the balances map is never nil (writing to a nil map panics)
+func NewMultiWallet(rates RateProvider) *MultiWallet {
+	return &MultiWallet{
+		balances: make(map[Currency]int),
+		rates:    rates,
+	}
+}
+
+func (w *MultiWallet) Deposit(amount Money) error {
+	if amount.Amount < 0 {
+		return ErrNegativeAmount
+	}
+	w.balances[amount.Currency] += amount.Amount
+	return nil
+}
+
+// Withdraw only takes from the balance in the same currency,
+// having 100 USD does not mean you can withdraw 50 GBP unless you Exchange it first.
+func (w *MultiWallet) Withdraw(amount Money) error {
+	if amount.Amount < 0 {
+		return ErrNegativeAmount
+	}
+
+	if amount.Amount > w.balances[amount.Currency] {
+		return ErrInsufficientFunds
+	}
+
+	w.balances[amount.Currency] -= amount.Amount
+	return nil
+}
+
+// Balance of a currency the wallet has never seen is just zero, as reading a missing map key gives the zero value.
+func (w *MultiWallet) Balance(currency Currency) Money {
+	return Money{w.balances[currency], currency}
+}
+
+// Exchange explicitly moves `amount` out of its currency and into `to` at the current rate.
+func (w *MultiWallet) Exchange(amount Money, to Currency) (Money, error) {
+	converted, err := Convert(amount, to, w.rates)
+	if err != nil {
+		return Money{}, err
+	}
+
+	if err := w.Withdraw(amount); err != nil {
+		return Money{}, err
+	}
+
+	w.balances[to] += converted.Amount
+	return converted, nil
+}
+
+// Total values every balance in a single currency, without changing what the wallet holds.
+func (w *MultiWallet) Total(in Currency) (Money, error) {
+	total := Money{0, in}
+	for currency, amount := range w.balances {
+		converted, err := Convert(Money{amount, currency}, in, w.rates)
+		if err != nil {
+			return Money{}, err
+		}
+		total.Amount += converted.Amount
+	}
+	return total, nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+var testRates = StaticRates{
+	{"GBP", "USD"}: 1.25,
+	{"EUR", "USD"}: 1.10,
+}
+
+func TestMoney(t *testing.T) {
+	t.Run("add same currency", func(t *testing.T) {
+		got, err := Money{10, "GBP"}.Add(Money{5, "GBP"})
+
+		assertNoError(t, err)
+		assertMoney(t, got, Money{15, "GBP"})
+	})
+
+	t.Run("add different currencies", func(t *testing.T) {
+		_, err := Money{10, "GBP"}.Add(Money{5, "USD"})
+
+		assertError(t, err, ErrCurrencyMismatch)
+	})
+
+	t.Run("convert with a known rate", func(t *testing.T) {
+		got, err := Convert(Money{100, "GBP"}, "USD", testRates)
+
+		assertNoError(t, err)
+		assertMoney(t, got, Money{125, "USD"})
+	})
+
+	t.Run("convert using the inverse rate", func(t *testing.T) {
+		got, err := Convert(Money{125, "USD"}, "GBP", testRates)
+
+		assertNoError(t, err)
+		assertMoney(t, got, Money{100, "GBP"})
+	})
+
+	t.Run("convert without a rate", func(t *testing.T) {
+		_, err := Convert(Money{100, "GBP"}, "JPY", testRates)
+
+		// the error is wrapped with the currencies, so we use `errors.Is` rather than `==`
+		if !errors.Is(err, ErrNoExchangeRate) {
+			t.Errorf("got %v want %v", err, ErrNoExchangeRate)
+		}
+	})
+}
+
+func TestMultiWallet(t *testing.T) {
+	t.Run("keeps separate balances", func(t *testing.T) {
+		wallet := NewMultiWallet(testRates)
+
+		assertNoError(t, wallet.Deposit(Money{10, "GBP"}))
+		assertNoError(t, wallet.Deposit(Money{20, "USD"}))
+
+		assertMoney(t, wallet.Balance("GBP"), Money{10, "GBP"})
+		assertMoney(t, wallet.Balance("USD"), Money{20, "USD"})
+		assertMoney(t, wallet.Balance("EUR"), Money{0, "EUR"})
+	})
+
+	t.Run("withdraw from another currency's balance", func(t *testing.T) {
+		wallet := NewMultiWallet(testRates)
+		assertNoError(t, wallet.Deposit(Money{100, "USD"}))
+
+		err := wallet.Withdraw(Money{10, "GBP"})
+
+		assertError(t, err, ErrInsufficientFunds)
+		assertMoney(t, wallet.Balance("USD"), Money{100, "USD"})
+	})
+
+	t.Run("negative deposit", func(t *testing.T) {
+		wallet := NewMultiWallet(testRates)
+
+		assertError(t, wallet.Deposit(Money{-10, "GBP"}), ErrNegativeAmount)
+	})
+
+	t.Run("exchange", func(t *testing.T) {
+		wallet := NewMultiWallet(testRates)
+		assertNoError(t, wallet.Deposit(Money{100, "GBP"}))
+
+		got, err := wallet.Exchange(Money{40, "GBP"}, "USD")
+
+		assertNoError(t, err)
+		assertMoney(t, got, Money{50, "USD"})
+		assertMoney(t, wallet.Balance("GBP"), Money{60, "GBP"})
+		assertMoney(t, wallet.Balance("USD"), Money{50, "USD"})
+	})
+
+	t.Run("exchange more than the balance", func(t *testing.T) {
+		wallet := NewMultiWallet(testRates)
+		assertNoError(t, wallet.Deposit(Money{10, "GBP"}))
+
+		_, err := wallet.Exchange(Money{40, "GBP"}, "USD")
+
+		assertError(t, err, ErrInsufficientFunds)
+		assertMoney(t, wallet.Balance("USD"), Money{0, "USD"})
+	})
+
+	t.Run("total in one currency", func(t *testing.T) {
+		wallet := NewMultiWallet(testRates)
+		assertNoError(t, wallet.Deposit(Money{100, "GBP"}))
+		assertNoError(t, wallet.Deposit(Money{25, "USD"}))
+
+		got, err := wallet.Total("USD")
+
+		assertNoError(t, err)
+		assertMoney(t, got, Money{150, "USD"})
+	})
+}
+
+func assertMoney(t testing.TB, got, want Money) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %s want %s", got, want)
+	}
+}
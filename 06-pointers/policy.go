@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+// Withdrawal policies
+// `Withdraw` used to hard-code one rule: you can't take out more than the balance.
+// Instead of adding more and more `if` statements to Withdraw, each rule becomes its own type
+// that satisfies a `WithdrawalPolicy` interface. They are injected into the wallet, the same way
+// the Sleeper is injected into Countdown in the mocking chapter.
+
+// each policy has its own error so callers can tell *why* a withdrawal was refused
+var (
+	ErrOverdraftLimitExceeded = errors.New("cannot withdraw, overdraft limit exceeded")
+	ErrDailyLimitExceeded     = errors.New("cannot withdraw, daily withdrawal limit exceeded")
+	ErrBelowMinimumBalance    = errors.New("cannot withdraw, balance would fall below the minimum")
+)
+
+type WithdrawalPolicy interface {
+	// Allow returns an error if withdrawing amount from balance breaks the rule
+	Allow(balance, amount Bitcoin) error
+	// Withdrawn is called once a withdrawal has actually happened,
+	// so policies that need to remember past withdrawals (like a daily cap) can keep track
+	Withdrawn(amount Bitcoin)
+}
+
+// NoOverdraft is the original rule and is used when a wallet has no other policies
+type NoOverdraft struct{}
+
+func (NoOverdraft) Allow(balance, amount Bitcoin) error {
+	if amount > balance {
+		return ErrInsufficientFunds
+	}
+	return nil
+}
+
+func (NoOverdraft) Withdrawn(Bitcoin) {}
+
+// OverdraftLimit lets the balance go negative, but no further than -Limit
+type OverdraftLimit struct {
+	Limit Bitcoin
+}
+
+func (o OverdraftLimit) Allow(balance, amount Bitcoin) error {
+	if balance-amount < -o.Limit {
+		return ErrOverdraftLimitExceeded
+	}
+	return nil
+}
+
+func (OverdraftLimit) Withdrawn(Bitcoin) {}
+
+// MinimumBalance refuses any withdrawal that would leave less than Minimum in the wallet
+type MinimumBalance struct {
+	Minimum Bitcoin
+}
+
+func (m MinimumBalance) Allow(balance, amount Bitcoin) error {
+	if balance-amount < m.Minimum {
+		return ErrBelowMinimumBalance
+	}
+	return nil
+}
+
+func (MinimumBalance) Withdrawn(Bitcoin) {}
+
+// DailyLimit caps the total withdrawn in a calendar day.
+// It needs to know what the time is, but calling `time.Now` directly would make it impossible to test
+// without waiting a day! So like `ConfigurableSleeper` we inject the function instead.
+type DailyLimit struct {
+	limit     Bitcoin
+	now       func() time.Time
+	day       time.Time
+	withdrawn Bitcoin
+}
+
+// it has state, so use a constructor and a pointer rather than letting people copy it around
+func NewDailyLimit(limit Bitcoin, now func() time.Time) *DailyLimit {
+	return &DailyLimit{limit: limit, now: now}
+}
+
+func (d *DailyLimit) Allow(_, amount Bitcoin) error {
+	if d.withdrawnToday()+amount > d.limit {
+		return ErrDailyLimitExceeded
+	}
+	return nil
+}
+
+func (d *DailyLimit) Withdrawn(amount Bitcoin) {
+	d.withdrawn = d.withdrawnToday() + amount
+	d.day = today(d.now())
+}
+
+// the running total only counts if it was for today, otherwise it's a new day and we start again
+func (d *DailyLimit) withdrawnToday() Bitcoin {
+	if !d.day.Equal(today(d.now())) {
+		return 0
+	}
+	return d.withdrawn
+}
+
+// `Truncate(24 * time.Hour)` would work in UTC only, so build midnight in the clock's own location
+func today(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// Policies combines several policies into one - every policy must allow the withdrawal.
+// It is a slice type that itself satisfies the interface, so a combination can be used anywhere a single policy can.
+type Policies []WithdrawalPolicy
+
+func (p Policies) Allow(balance, amount Bitcoin) error {
+	for _, policy := range p {
+		if err := policy.Allow(balance, amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// only tell the policies a withdrawal happened once *all* of them have allowed it
+func (p Policies) Withdrawn(amount Bitcoin) {
+	for _, policy := range p {
+		policy.Withdrawn(amount)
+	}
+}
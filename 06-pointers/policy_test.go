@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithdrawalPolicies(t *testing.T) {
+	t.Run("a wallet without policies cannot go overdrawn", func(t *testing.T) {
+		wallet := NewWallet()
+		wallet.Deposit(Bitcoin(10))
+
+		err := wallet.Withdraw(Bitcoin(20))
+
+		assertError(t, err, ErrInsufficientFunds)
+		assertBalance(t, *wallet, Bitcoin(10))
+	})
+
+	t.Run("overdraft within the limit", func(t *testing.T) {
+		wallet := NewWallet(OverdraftLimit{Bitcoin(50)})
+		wallet.Deposit(Bitcoin(10))
+
+		err := wallet.Withdraw(Bitcoin(60))
+
+		assertNoError(t, err)
+		assertBalance(t, *wallet, Bitcoin(-50))
+	})
+
+	t.Run("overdraft beyond the limit", func(t *testing.T) {
+		wallet := NewWallet(OverdraftLimit{Bitcoin(50)})
+		wallet.Deposit(Bitcoin(10))
+
+		err := wallet.Withdraw(Bitcoin(61))
+
+		assertError(t, err, ErrOverdraftLimitExceeded)
+		assertBalance(t, *wallet, Bitcoin(10))
+	})
+
+	t.Run("minimum balance", func(t *testing.T) {
+		wallet := NewWallet(MinimumBalance{Bitcoin(5)})
+		wallet.Deposit(Bitcoin(10))
+
+		assertNoError(t, wallet.Withdraw(Bitcoin(5)))
+		assertError(t, wallet.Withdraw(Bitcoin(1)), ErrBelowMinimumBalance)
+		assertBalance(t, *wallet, Bitcoin(5))
+	})
+
+	t.Run("daily limit resets the next day", func(t *testing.T) {
+		clock := &stubClock{time.Date(2024, time.October, 20, 9, 0, 0, 0, time.UTC)}
+		wallet := NewWallet(NoOverdraft{}, NewDailyLimit(Bitcoin(30), clock.Now))
+		wallet.Deposit(Bitcoin(100))
+
+		assertNoError(t, wallet.Withdraw(Bitcoin(20)))
+		assertError(t, wallet.Withdraw(Bitcoin(20)), ErrDailyLimitExceeded)
+
+		clock.now = clock.now.Add(15 * time.Hour)
+
+		assertNoError(t, wallet.Withdraw(Bitcoin(20)))
+		assertBalance(t, *wallet, Bitcoin(60))
+	})
+
+	t.Run("refused withdrawals don't count towards the daily limit", func(t *testing.T) {
+		clock := &stubClock{time.Date(2024, time.October, 20, 9, 0, 0, 0, time.UTC)}
+		wallet := NewWallet(NewDailyLimit(Bitcoin(30), clock.Now), NoOverdraft{})
+		wallet.Deposit(Bitcoin(10))
+
+		// allowed by the daily limit but refused by NoOverdraft
+		assertError(t, wallet.Withdraw(Bitcoin(25)), ErrInsufficientFunds)
+
+		wallet.Deposit(Bitcoin(100))
+		assertNoError(t, wallet.Withdraw(Bitcoin(30)))
+	})
+
+	t.Run("policies are checked in order", func(t *testing.T) {
+		wallet := NewWallet(MinimumBalance{Bitcoin(0)}, OverdraftLimit{Bitcoin(10)})
+
+		err := wallet.Withdraw(Bitcoin(5))
+
+		assertError(t, err, ErrBelowMinimumBalance)
+	})
+}
+
+// a stub clock - the test moves time forward by changing `now`
+type stubClock struct {
+	now time.Time
+}
+
+func (s *stubClock) Now() time.Time {
+	return s.now
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Persisting wallets
+// A Wallet only lives in memory, so when the program stops every balance is lost.
+// Rather than saving the balance itself, we save every change that was made to it (a "ledger").
+// The balance can always be worked out again by replaying the ledger, and we also get a history for free.
+
+type EntryKind string
+
+const (
+	EntryOpened     EntryKind = "opened"
+	EntryDeposit    EntryKind = "deposit"
+	EntryWithdrawal EntryKind = "withdrawal"
+)
+
+type LedgerEntry struct {
+	WalletID string    `json:"wallet"`
+	Kind     EntryKind `json:"kind"`
+	Amount   Bitcoin   `json:"amount"`
+	Time     time.Time `json:"time"`
+}
+
+var (
+	ErrWalletNotFound = errors.New("wallet not found")
+	ErrWalletExists   = errors.New("wallet already exists")
+	ErrUnknownEntry   = errors.New("unknown ledger entry kind")
+)
+
+// WalletStore is the dependency wallets are saved to.
+// Like the Store in the context chapter it is an interface, so tests can use the in-memory version
+// and real code can use the file-backed one. Its methods don't take a context, api.go explains why.
+type WalletStore interface {
+	Append(entry LedgerEntry) error
+	Balance(id string) (Bitcoin, error)
+	Ledger(id string) ([]LedgerEntry, error)
+}
+
+// walletRecord is what both stores keep for each wallet
+type walletRecord struct {
+	Balance Bitcoin       `json:"balance"`
+	Entries []LedgerEntry `json:"entries"`
+}
+
+// ledgers holds every wallet's record and knows how to apply entries to them.
+// Both stores use it so they can't disagree about what an entry means.
+type ledgers map[string]*walletRecord
+
+// check makes sure the entry can be applied, without changing anything
+func (l ledgers) check(entry LedgerEntry) error {
+	_, exists := l[entry.WalletID]
+
+	switch entry.Kind {
+	case EntryOpened:
+		if exists {
+			return ErrWalletExists
+		}
+	case EntryDeposit, EntryWithdrawal:
+		if !exists {
+			return ErrWalletNotFound
+		}
+	default:
+		return ErrUnknownEntry
+	}
+	return nil
+}
+
+func (l ledgers) apply(entry LedgerEntry) error {
+	if err := l.check(entry); err != nil {
+		return err
+	}
+
+	if entry.Kind == EntryOpened {
+		l[entry.WalletID] = &walletRecord{}
+	}
+
+	record := l[entry.WalletID]
+	switch entry.Kind {
+	case EntryDeposit:
+		record.Balance += entry.Amount
+	case EntryWithdrawal:
+		record.Balance -= entry.Amount
+	}
+	record.Entries = append(record.Entries, entry)
+	return nil
+}
+
+func (l ledgers) balance(id string) (Bitcoin, error) {
+	record, ok := l[id]
+	if !ok {
+		return 0, ErrWalletNotFound
+	}
+	return record.Balance, nil
+}
+
+// return a copy, so callers can't change the store's entries behind its back
+func (l ledgers) ledger(id string) ([]LedgerEntry, error) {
+	record, ok := l[id]
+	if !ok {
+		return nil, ErrWalletNotFound
+	}
+	return append([]LedgerEntry(nil), record.Entries...), nil
+}
+
+// InMemoryWalletStore keeps everything in a map - it's what tests use.
+// It has a mutex (like the Counter in the sync chapter) so it can be shared between goroutines.
+type InMemoryWalletStore struct {
+	mu      sync.Mutex
+	ledgers ledgers
+}
+
+func NewInMemoryWalletStore() *InMemoryWalletStore {
+	return &InMemoryWalletStore{ledgers: ledgers{}}
+}
+
+func (s *InMemoryWalletStore) Append(entry LedgerEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ledgers.apply(entry)
+}
+
+func (s *InMemoryWalletStore) Balance(id string) (Bitcoin, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ledgers.balance(id)
+}
+
+func (s *InMemoryWalletStore) Ledger(id string) ([]LedgerEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ledgers.ledger(id)
+}
+
+// StoredWallet is a Wallet that records every change in a WalletStore.
+// The Wallet still makes the decisions (e.g. its withdrawal policies), the store just remembers them.
+type StoredWallet struct {
+	id     string
+	store  WalletStore
+	wallet *Wallet
+	now    func() time.Time
+}
+
+// OpenWallet loads the wallet with the given id from the store, or creates it if it doesn't exist yet.
+func OpenWallet(store WalletStore, id string, policies ...WithdrawalPolicy) (*StoredWallet, error) {
+	wallet := NewWallet(policies...)
+
+	balance, err := store.Balance(id)
+	switch err {
+	case nil:
+		wallet.balance = balance
+	case ErrWalletNotFound:
+		if err := store.Append(LedgerEntry{WalletID: id, Kind: EntryOpened, Time: time.Now()}); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	return &StoredWallet{id: id, store: store, wallet: wallet, now: time.Now}, nil
+}
+
+func (s *StoredWallet) ID() string {
+	return s.id
+}
+
+func (s *StoredWallet) Balance() Bitcoin {
+	return s.wallet.Balance()
+}
+
+// unlike Wallet.Deposit this can fail, because saving to the store can fail
+func (s *StoredWallet) Deposit(amount Bitcoin) error {
+	if err := s.store.Append(s.entry(EntryDeposit, amount)); err != nil {
+		return err
+	}
+	s.wallet.Deposit(amount)
+	return nil
+}
+
+func (s *StoredWallet) Withdraw(amount Bitcoin) error {
+	// ask the policies first, we don't want to save a withdrawal that isn't allowed
+	policy := s.wallet.withdrawalPolicy()
+	if err := policy.Allow(s.wallet.balance, amount); err != nil {
+		return err
+	}
+
+	if err := s.store.Append(s.entry(EntryWithdrawal, amount)); err != nil {
+		return err
+	}
+
+	s.wallet.balance -= amount
+	policy.Withdrawn(amount)
+	return nil
+}
+
+func (s *StoredWallet) entry(kind EntryKind, amount Bitcoin) LedgerEntry {
+	return LedgerEntry{WalletID: s.id, Kind: kind, Amount: amount, Time: s.now()}
+}
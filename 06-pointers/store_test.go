@@ -0,0 +1,109 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// both stores should behave the same, so run the same tests against each of them
+func TestWalletStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) WalletStore{
+		"in memory": func(t *testing.T) WalletStore {
+			return NewInMemoryWalletStore()
+		},
+		"file": func(t *testing.T) WalletStore {
+			return openTestFileStore(t, t.TempDir(), 0)
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			testWalletStore(t, newStore)
+		})
+	}
+}
+
+func testWalletStore(t *testing.T, newStore func(t *testing.T) WalletStore) {
+	t.Run("unknown wallet", func(t *testing.T) {
+		store := newStore(t)
+
+		_, err := store.Balance("missing")
+		assertError(t, err, ErrWalletNotFound)
+
+		err = store.Append(LedgerEntry{WalletID: "missing", Kind: EntryDeposit, Amount: 10})
+		assertError(t, err, ErrWalletNotFound)
+	})
+
+	t.Run("balance from ledger", func(t *testing.T) {
+		store := newStore(t)
+		entries := []LedgerEntry{
+			testEntry("adam", EntryOpened, 0),
+			testEntry("adam", EntryDeposit, 20),
+			testEntry("adam", EntryWithdrawal, 5),
+		}
+
+		for _, entry := range entries {
+			assertNoError(t, store.Append(entry))
+		}
+
+		assertStoredBalance(t, store, "adam", Bitcoin(15))
+		assertLedger(t, store, "adam", entries)
+	})
+
+	t.Run("opening twice", func(t *testing.T) {
+		store := newStore(t)
+		assertNoError(t, store.Append(testEntry("adam", EntryOpened, 0)))
+
+		err := store.Append(testEntry("adam", EntryOpened, 0))
+
+		assertError(t, err, ErrWalletExists)
+	})
+
+	t.Run("stored wallet", func(t *testing.T) {
+		store := newStore(t)
+		wallet, err := OpenWallet(store, "adam")
+		assertNoError(t, err)
+
+		assertNoError(t, wallet.Deposit(Bitcoin(20)))
+		assertNoError(t, wallet.Withdraw(Bitcoin(5)))
+		assertError(t, wallet.Withdraw(Bitcoin(100)), ErrInsufficientFunds)
+
+		// opening it again picks up the saved balance rather than starting from zero
+		reopened, err := OpenWallet(store, "adam")
+		assertNoError(t, err)
+
+		if reopened.Balance() != Bitcoin(15) {
+			t.Errorf("got %s want %s", reopened.Balance(), Bitcoin(15))
+		}
+		assertStoredBalance(t, store, "adam", Bitcoin(15))
+	})
+}
+
+// times are in UTC without a monotonic reading, so they compare equal after a round trip through JSON
+func testEntry(id string, kind EntryKind, amount Bitcoin) LedgerEntry {
+	return LedgerEntry{
+		WalletID: id,
+		Kind:     kind,
+		Amount:   amount,
+		Time:     time.Date(2024, time.October, 20, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func assertStoredBalance(t testing.TB, store WalletStore, id string, want Bitcoin) {
+	t.Helper()
+	got, err := store.Balance(id)
+	assertNoError(t, err)
+	if got != want {
+		t.Errorf("got %s want %s", got, want)
+	}
+}
+
+func assertLedger(t testing.TB, store WalletStore, id string, want []LedgerEntry) {
+	t.Helper()
+	got, err := store.Ledger(id)
+	assertNoError(t, err)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
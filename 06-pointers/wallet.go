@@ -51,6 +51,18 @@ type Bitcoin int
 
 type Wallet struct {
 	balance Bitcoin
+	// the rules for withdrawing, see policy.go. A nil policy means the original "no overdraft" rule.
+	policy WithdrawalPolicy
+}
+
+// NewWallet creates a wallet with the given withdrawal policies.
+// The policies replace the default rule rather than adding to it, so to keep the "no overdraft" rule
+// alongside e.g. a daily limit, pass `NoOverdraft{}` as well.
+func NewWallet(policies ...WithdrawalPolicy) *Wallet {
+	if len(policies) == 0 {
+		return &Wallet{}
+	}
+	return &Wallet{policy: Policies(policies)}
 }
 
 func (w *Wallet) Deposit(amount Bitcoin) {
@@ -87,17 +99,38 @@ func (b Bitcoin) String() string {
 // the var keyword allows us to define values global to the package (so we can use it in tests)
 var ErrInsufficientFunds = errors.New("cannot withdraw, insufficient funds")
 
+// func (w *Wallet) Withdraw(amount Bitcoin) error {
+
+// 	if amount > w.balance {
+// 		// `errors.New` creates a a new error with message
+// 		return ErrInsufficientFunds
+// 	}
+
+// 	w.balance -= amount
+// 	return nil
+// }
+
+// the single hard-coded rule above is now one of several pluggable withdrawal policies (see policy.go)
 func (w *Wallet) Withdraw(amount Bitcoin) error {
+	policy := w.withdrawalPolicy()
 
-	if amount > w.balance {
-		// `errors.New` creates a a new error with message
-		return ErrInsufficientFunds
+	if err := policy.Allow(w.balance, amount); err != nil {
+		return err
 	}
 
 	w.balance -= amount
+	policy.Withdrawn(amount)
 	return nil
 }
 
+// the zero value Wallet{} has no policy, so fall back to the original rule
+func (w *Wallet) withdrawalPolicy() WithdrawalPolicy {
+	if w.policy == nil {
+		return NoOverdraft{}
+	}
+	return w.policy
+}
+
 // Summary:
 // Go copies valies when you pass them to function/methods, so if you need to mutate state then use a pointer to that state
 // pointers can be nil, you must check if it's nil otherwise it might cause a runtime exception.
@@ -44,7 +44,7 @@ func TestWallet(t *testing.T) {
 
 	t.Run("withdraw insufficient funds", func(t *testing.T) {
 		startingBalance := Bitcoin(20)
-		wallet := Wallet{startingBalance}
+		wallet := Wallet{balance: startingBalance}
 		// we need to add a return type to Withdraw for this to work
 		err := wallet.Withdraw(Bitcoin(100))
 
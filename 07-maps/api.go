@@ -0,0 +1,136 @@
+package dictionary
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// A JSON API for the glossary, so it can be used without writing Go
+//
+//	GET    /words/{word}                                       look up a word
+//	POST   /words/{word}   {"definition": "a yellow fruit"}    add a new word
+//	PUT    /words/{word}   {"definition": "a yellow fruit"}    change the definition of a word
+//	DELETE /words/{word}                                       remove a word
+//
+// The errors the dictionary already returns are turned into HTTP status codes, see statusFor.
+
+const ErrInvalidRequest = DictionaryErr("request body should be JSON with a definition")
+
+type DictionaryServer struct {
+	// a plain Dictionary isn't safe to use from more than one goroutine,
+	// and the http server handles every request in its own goroutine
+	mu      sync.Mutex
+	store   DictionaryStore
+	handler http.Handler
+}
+
+type definitionRequest struct {
+	Definition string `json:"definition"`
+}
+
+type wordResponse struct {
+	Word       string `json:"word"`
+	Definition string `json:"definition"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func NewDictionaryServer(store DictionaryStore) *DictionaryServer {
+	s := &DictionaryServer{store: store}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /words/{word}", s.search)
+	mux.HandleFunc("POST /words/{word}", s.add)
+	mux.HandleFunc("PUT /words/{word}", s.update)
+	mux.HandleFunc("DELETE /words/{word}", s.delete)
+	s.handler = mux
+
+	return s
+}
+
+func (s *DictionaryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+func (s *DictionaryServer) search(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	word := r.PathValue("word")
+	definition, err := s.store.Search(word)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, wordResponse{word, definition})
+}
+
+func (s *DictionaryServer) add(w http.ResponseWriter, r *http.Request) {
+	s.change(w, r, http.StatusCreated, s.store.Add)
+}
+
+func (s *DictionaryServer) update(w http.ResponseWriter, r *http.Request) {
+	s.change(w, r, http.StatusOK, s.store.Update)
+}
+
+// change reads the definition from the request body and passes it to add or update
+func (s *DictionaryServer) change(w http.ResponseWriter, r *http.Request, successStatus int, apply func(word, definition string) error) {
+	var req definitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Definition == "" {
+		writeError(w, ErrInvalidRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	word := r.PathValue("word")
+	if err := apply(word, req.Definition); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, successStatus, wordResponse{word, req.Definition})
+}
+
+func (s *DictionaryServer) delete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.store.Delete(r.PathValue("word")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrWordDoesNotExist):
+		return http.StatusNotFound
+	case errors.Is(err, ErrWordExists):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidRequest):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusFor(err), errorResponse{err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
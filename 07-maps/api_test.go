@@ -0,0 +1,126 @@
+package dictionary
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDictionaryServer(t *testing.T) {
+	forEachStore(t, func(t *testing.T, newStore newStoreFunc) {
+		t.Run("get a word", func(t *testing.T) {
+			server := NewDictionaryServer(newStore(t, Dictionary{"test": "this is just a test"}))
+
+			response := serve(server, http.MethodGet, "/words/test", "")
+
+			assertStatus(t, response, http.StatusOK)
+			assertBody(t, response, `{"word":"test","definition":"this is just a test"}`)
+		})
+
+		t.Run("get an unknown word", func(t *testing.T) {
+			server := NewDictionaryServer(newStore(t, Dictionary{}))
+
+			response := serve(server, http.MethodGet, "/words/unknown", "")
+
+			assertStatus(t, response, http.StatusNotFound)
+			assertBody(t, response, `{"error":"could not find the word you were looking for"}`)
+		})
+
+		t.Run("words are unescaped from the path", func(t *testing.T) {
+			server := NewDictionaryServer(newStore(t, Dictionary{"ice cream": "a frozen dessert"}))
+
+			response := serve(server, http.MethodGet, "/words/ice%20cream", "")
+
+			assertStatus(t, response, http.StatusOK)
+			assertBody(t, response, `{"word":"ice cream","definition":"a frozen dessert"}`)
+		})
+
+		t.Run("add a word", func(t *testing.T) {
+			store := newStore(t, Dictionary{})
+			server := NewDictionaryServer(store)
+
+			response := serve(server, http.MethodPost, "/words/banana", `{"definition": "a yellow fruit"}`)
+
+			assertStatus(t, response, http.StatusCreated)
+			assertDefinition(t, store, "banana", "a yellow fruit")
+		})
+
+		t.Run("add an existing word", func(t *testing.T) {
+			store := newStore(t, Dictionary{"test": "this is just a test"})
+			server := NewDictionaryServer(store)
+
+			response := serve(server, http.MethodPost, "/words/test", `{"definition": "new definition"}`)
+
+			assertStatus(t, response, http.StatusConflict)
+			assertDefinition(t, store, "test", "this is just a test")
+		})
+
+		t.Run("update a word", func(t *testing.T) {
+			store := newStore(t, Dictionary{"test": "this is just a test"})
+			server := NewDictionaryServer(store)
+
+			response := serve(server, http.MethodPut, "/words/test", `{"definition": "new definition"}`)
+
+			assertStatus(t, response, http.StatusOK)
+			assertBody(t, response, `{"word":"test","definition":"new definition"}`)
+			assertDefinition(t, store, "test", "new definition")
+		})
+
+		t.Run("update an unknown word", func(t *testing.T) {
+			server := NewDictionaryServer(newStore(t, Dictionary{}))
+
+			response := serve(server, http.MethodPut, "/words/test", `{"definition": "new definition"}`)
+
+			assertStatus(t, response, http.StatusNotFound)
+			assertBody(t, response, `{"error":"cannot update word because it does not exist"}`)
+		})
+
+		t.Run("bad request bodies", func(t *testing.T) {
+			server := NewDictionaryServer(newStore(t, Dictionary{}))
+
+			for _, body := range []string{"", "not json", `{"definition": ""}`} {
+				response := serve(server, http.MethodPost, "/words/test", body)
+				assertStatus(t, response, http.StatusBadRequest)
+			}
+		})
+
+		t.Run("delete a word", func(t *testing.T) {
+			store := newStore(t, Dictionary{"test": "this is just a test"})
+			server := NewDictionaryServer(store)
+
+			response := serve(server, http.MethodDelete, "/words/test", "")
+
+			assertStatus(t, response, http.StatusNoContent)
+			_, err := store.Search("test")
+			assertError(t, err, ErrNotFound)
+		})
+
+		t.Run("method not allowed", func(t *testing.T) {
+			server := NewDictionaryServer(newStore(t, Dictionary{}))
+
+			response := serve(server, http.MethodPatch, "/words/test", "")
+
+			assertStatus(t, response, http.StatusMethodNotAllowed)
+		})
+	})
+}
+
+func serve(server http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, request)
+	return response
+}
+
+func assertStatus(t testing.TB, response *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if response.Code != want {
+		t.Errorf("got status %d want %d, body %q", response.Code, want, response.Body.String())
+	}
+}
+
+func assertBody(t testing.TB, response *httptest.ResponseRecorder, want string) {
+	t.Helper()
+	assertStrings(t, response.Body.String(), want)
+}
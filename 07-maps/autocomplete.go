@@ -0,0 +1,191 @@
+package dictionary
+
+import "sort"
+
+// Autocomplete
+// A map can only find a word if you give it the *exact* key. To find every word starting with "te"
+// we'd have to look at every key, and then sort them, as maps have no order.
+// A "trie" (prefix tree) stores words one letter per level, so all the words sharing a prefix
+// live under the same node - we walk down the prefix and then collect everything beneath it.
+
+type trieNode struct {
+	char byte
+	// children are kept sorted by char, so visiting them in order gives words in lexicographic order
+	children []*trieNode
+	// word is true when the path from the root to this node spells a whole word
+	word bool
+}
+
+// child returns the child for c, and where it is (or would be) in the sorted children
+func (n *trieNode) child(c byte) (*trieNode, int) {
+	i := sort.Search(len(n.children), func(i int) bool { return n.children[i].char >= c })
+	if i < len(n.children) && n.children[i].char == c {
+		return n.children[i], i
+	}
+	return nil, i
+}
+
+type trie struct {
+	root trieNode
+}
+
+func (t *trie) insert(word string) {
+	node := &t.root
+	for i := 0; i < len(word); i++ {
+		next, at := node.child(word[i])
+		if next == nil {
+			next = &trieNode{char: word[i]}
+			// make room at `at` and put the new child there, keeping the slice sorted
+			node.children = append(node.children, nil)
+			copy(node.children[at+1:], node.children[at:])
+			node.children[at] = next
+		}
+		node = next
+	}
+	node.word = true
+}
+
+func (t *trie) remove(word string) {
+	// remember the path so we can prune nodes that no longer lead to any word
+	path := []*trieNode{&t.root}
+	node := &t.root
+	for i := 0; i < len(word); i++ {
+		next, _ := node.child(word[i])
+		if next == nil {
+			return
+		}
+		node = next
+		path = append(path, node)
+	}
+	node.word = false
+
+	for i := len(path) - 1; i > 0; i-- {
+		current := path[i]
+		if current.word || len(current.children) > 0 {
+			return
+		}
+		parent := path[i-1]
+		_, at := parent.child(current.char)
+		parent.children = append(parent.children[:at], parent.children[at+1:]...)
+	}
+}
+
+// withPrefix returns up to limit words starting with prefix in lexicographic order, or all of them if limit is 0
+func (t *trie) withPrefix(prefix string, limit int) []string {
+	node := &t.root
+	for i := 0; i < len(prefix); i++ {
+		node, _ = node.child(prefix[i])
+		if node == nil {
+			return nil
+		}
+	}
+
+	var words []string
+	buf := []byte(prefix)
+
+	// depth first, visiting children in order. Returns false once we have enough words.
+	var collect func(n *trieNode) bool
+	collect = func(n *trieNode) bool {
+		if n.word {
+			words = append(words, string(buf))
+			if limit > 0 && len(words) >= limit {
+				return false
+			}
+		}
+		for _, child := range n.children {
+			buf = append(buf, child.char)
+			more := collect(child)
+			buf = buf[:len(buf)-1]
+			if !more {
+				return false
+			}
+		}
+		return true
+	}
+	collect(node)
+
+	return words
+}
+
+// IndexedDictionary is a Dictionary that also keeps indexes of its words, so it can autocomplete and suggest.
+// The map is still used for exact lookups; every Add and Delete updates the indexes too.
+// We don't embed Dictionary, as its methods would then be public and could change the map
+// without updating the indexes.
+type IndexedDictionary struct {
+	words Dictionary
+	index *trie
+	// fuzzy is used for "did you mean" suggestions, see suggest.go
+	fuzzy *bkTree
+	// text indexes the definitions, see fulltext.go
+	text *textIndex
+}
+
+func NewIndexedDictionary() *IndexedDictionary {
+	return &IndexedDictionary{
+		words: Dictionary{},
+		index: &trie{},
+		fuzzy: &bkTree{},
+		text:  newTextIndex(),
+	}
+}
+
+// Search returns a *NotFoundError with suggestions for unknown words,
+// use `errors.Is(err, ErrNotFound)` to check for it.
+func (d *IndexedDictionary) Search(word string) (string, error) {
+	definition, err := d.words.Search(word)
+	if err == ErrNotFound {
+		return "", &NotFoundError{Word: word, Suggestions: d.Suggest(word)}
+	}
+	return definition, err
+}
+
+func (d *IndexedDictionary) Add(word, definition string) error {
+	if err := d.words.Add(word, definition); err != nil {
+		return err
+	}
+	d.index.insert(word)
+	d.fuzzy.insert(word)
+	d.text.add(word, definition)
+	return nil
+}
+
+// updating a definition doesn't change which words there are, so only the text index needs updating
+func (d *IndexedDictionary) Update(word, definition string) error {
+	old, err := d.words.Search(word)
+	if err != nil {
+		return ErrWordDoesNotExist
+	}
+	if err := d.words.Update(word, definition); err != nil {
+		return err
+	}
+	d.text.remove(word, old)
+	d.text.add(word, definition)
+	return nil
+}
+
+func (d *IndexedDictionary) Delete(word string) error {
+	definition, err := d.words.Search(word)
+	if err != nil {
+		// like Dictionary, deleting a word that doesn't exist has no effect
+		return nil
+	}
+	if err := d.words.Delete(word); err != nil {
+		return err
+	}
+	d.index.remove(word)
+	d.fuzzy.remove(word)
+	d.text.remove(word, definition)
+	return nil
+}
+
+// SearchDefinitions finds words whose definitions match the query, best match first.
+// A limit of 0 returns every match.
+func (d *IndexedDictionary) SearchDefinitions(query string, limit int) []DefinitionMatch {
+	return d.text.search(query, limit)
+}
+
+// SearchPrefix returns up to limit words that start with prefix, in lexicographic order.
+// A limit of 0 returns every match.
+func (d *IndexedDictionary) SearchPrefix(prefix string, limit int) []string {
+	return d.index.withPrefix(prefix, limit)
+}
@@ -0,0 +1,140 @@
+package dictionary
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSearchPrefix(t *testing.T) {
+	newDictionary := func(t testing.TB, words ...string) *IndexedDictionary {
+		t.Helper()
+		dictionary := NewIndexedDictionary()
+		for _, word := range words {
+			assertError(t, dictionary.Add(word, "definition of "+word), nil)
+		}
+		return dictionary
+	}
+
+	t.Run("words in lexicographic order", func(t *testing.T) {
+		dictionary := newDictionary(t, "test", "team", "tea", "ten", "apple", "te")
+
+		got := dictionary.SearchPrefix("te", 0)
+		want := []string{"te", "tea", "team", "ten", "test"}
+
+		assertWords(t, got, want)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		dictionary := newDictionary(t, "test", "team", "tea", "ten")
+
+		got := dictionary.SearchPrefix("te", 2)
+
+		assertWords(t, got, []string{"tea", "team"})
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		dictionary := newDictionary(t, "test")
+
+		assertWords(t, dictionary.SearchPrefix("x", 10), nil)
+	})
+
+	t.Run("empty prefix returns everything", func(t *testing.T) {
+		dictionary := newDictionary(t, "b", "a", "c")
+
+		assertWords(t, dictionary.SearchPrefix("", 0), []string{"a", "b", "c"})
+	})
+
+	t.Run("deleted words are not suggested", func(t *testing.T) {
+		dictionary := newDictionary(t, "tea", "team", "test")
+
+		dictionary.Delete("team")
+		dictionary.Delete("tea")
+
+		assertWords(t, dictionary.SearchPrefix("te", 0), []string{"test"})
+		assertWords(t, dictionary.SearchPrefix("tea", 0), nil)
+	})
+
+	t.Run("a failed add doesn't change the index", func(t *testing.T) {
+		dictionary := newDictionary(t, "test")
+
+		assertError(t, dictionary.Add("test", "again"), ErrWordExists)
+		dictionary.Delete("test")
+
+		assertWords(t, dictionary.SearchPrefix("te", 0), nil)
+	})
+
+	t.Run("update keeps the word", func(t *testing.T) {
+		dictionary := newDictionary(t, "test")
+
+		assertError(t, dictionary.Update("test", "new definition"), nil)
+
+		assertWords(t, dictionary.SearchPrefix("te", 0), []string{"test"})
+		assertDefinition(t, dictionary.words, "test", "new definition")
+	})
+}
+
+func assertWords(t testing.TB, got, want []string) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+// a 100k word corpus of random lowercase "words", the same every run
+func benchmarkCorpus() []string {
+	random := rand.New(rand.NewSource(1))
+	words := make([]string, 100_000)
+	for i := range words {
+		var word strings.Builder
+		for j := 0; j < 3+random.Intn(8); j++ {
+			word.WriteByte(byte('a' + random.Intn(26)))
+		}
+		words[i] = word.String()
+	}
+	return words
+}
+
+func BenchmarkSearchPrefix(b *testing.B) {
+	corpus := benchmarkCorpus()
+	indexed := NewIndexedDictionary()
+	plain := Dictionary{}
+	for _, word := range corpus {
+		indexed.Add(word, "definition")
+		plain.Add(word, "definition")
+	}
+
+	b.Run("trie", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			indexed.SearchPrefix("ab", 10)
+		}
+	})
+
+	// what we'd have to do without the index: check every key, then sort the matches
+	b.Run("scan map", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var matches []string
+			for word := range plain {
+				if strings.HasPrefix(word, "ab") {
+					matches = append(matches, word)
+				}
+			}
+			sort.Strings(matches)
+			if len(matches) > 10 {
+				matches = matches[:10]
+			}
+		}
+	})
+}
+
+func BenchmarkIndexedDictionaryAdd(b *testing.B) {
+	corpus := benchmarkCorpus()
+	for i := 0; i < b.N; i++ {
+		dictionary := NewIndexedDictionary()
+		for _, word := range corpus {
+			dictionary.Add(word, "definition")
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package dictionary
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Using a Dictionary as a cache
+// Looking definitions up in a slow service every time is wasteful, so CacheDictionary keeps the answers.
+// A cache can't keep everything forever though:
+//   - entries can expire after a time to live (TTL), so stale definitions are fetched again
+//   - there can be a capacity, and when it's full the least recently used (LRU) word is evicted
+//
+// For LRU we need to know the order words were used in. `container/list` is a doubly linked list:
+// moving an element to the front or removing it from the back doesn't need to shift anything along,
+// and the map points straight at each word's element so we never have to search the list.
+//
+// Like HistoryDictionary the clock is a function passed in, so the tests don't have to wait for entries to expire.
+
+type EvictionReason int
+
+const (
+	// EvictedCapacity means the cache was full and the word was the least recently used
+	EvictedCapacity EvictionReason = iota
+	// EvictedExpired means the word's TTL had passed
+	EvictedExpired
+)
+
+type CacheConfig struct {
+	// TTL is how long an entry lives for, unless it's added with AddWithTTL. 0 means forever.
+	TTL time.Duration
+	// Capacity is the most words the cache holds. 0 means there's no limit.
+	Capacity int
+	// Now is the clock, time.Now if it's nil
+	Now func() time.Time
+	// Load fetches a word Search can't find, it should return ErrNotFound for unknown words.
+	// If it's nil Search only looks in the cache.
+	Load func(word string) (string, error)
+	// OnEvict, if set, is called for every word that expires or is pushed out by Capacity.
+	// It is not called for Delete, as the caller already knows about that.
+	OnEvict func(word, definition string, reason EvictionReason)
+}
+
+type CacheDictionary struct {
+	mu     sync.Mutex
+	config CacheConfig
+	// most recently used at the front
+	order   *list.List
+	entries map[string]*list.Element
+	// evictions waiting to be passed to OnEvict, see unlock
+	evicted []eviction
+}
+
+type cacheEntry struct {
+	word       string
+	definition string
+	// zero means it never expires
+	expires time.Time
+}
+
+type eviction struct {
+	entry  cacheEntry
+	reason EvictionReason
+}
+
+func NewCacheDictionary(config CacheConfig) *CacheDictionary {
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return &CacheDictionary{
+		config:  config,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Search counts as using the word, so it moves to the front of the LRU order.
+// If the word isn't cached it's loaded with Load and kept for next time.
+func (c *CacheDictionary) Search(word string) (string, error) {
+	c.mu.Lock()
+	entry, ok := c.live(word)
+	c.unlock()
+
+	if ok {
+		return entry.definition, nil
+	}
+	if c.config.Load == nil {
+		return "", ErrNotFound
+	}
+
+	// the lock isn't held while loading, so one slow load doesn't hold up every other word.
+	// Two Searches for the same missing word may both load it, which is only wasted work.
+	definition, err := c.config.Load(word)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	// it may have been added or updated while we were loading, the cached version is newer than ours
+	if entry, ok := c.live(word); ok {
+		return entry.definition, nil
+	}
+	c.set(word, definition, c.config.TTL)
+	return definition, nil
+}
+
+func (c *CacheDictionary) Add(word, definition string) error {
+	return c.AddWithTTL(word, definition, c.config.TTL)
+}
+
+// AddWithTTL adds a word that expires after ttl instead of the TTL in the config. 0 means it never expires.
+func (c *CacheDictionary) AddWithTTL(word, definition string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.live(word); ok {
+		return ErrWordExists
+	}
+	c.set(word, definition, ttl)
+	return nil
+}
+
+// Update gives the word a fresh TTL, as the new definition hasn't had time to go stale
+func (c *CacheDictionary) Update(word, definition string) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.live(word); !ok {
+		return ErrWordDoesNotExist
+	}
+	c.set(word, definition, c.config.TTL)
+	return nil
+}
+
+func (c *CacheDictionary) Delete(word string) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if element, ok := c.entries[word]; ok {
+		c.remove(element)
+	}
+	return nil
+}
+
+// Len is the number of words in the cache, including expired ones that haven't been removed yet
+func (c *CacheDictionary) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+	return c.order.Len()
+}
+
+// PurgeExpired removes every expired word. Expired words are otherwise only removed when they're next used,
+// or when they reach the back of the LRU order.
+func (c *CacheDictionary) PurgeExpired() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.config.Now()
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		if entry := element.Value.(*cacheEntry); entry.expired(now) {
+			c.evict(element, EvictedExpired)
+		}
+		element = next
+	}
+}
+
+// live finds a word that hasn't expired and marks it as just used. c.mu must be held.
+func (c *CacheDictionary) live(word string) (cacheEntry, bool) {
+	element, ok := c.entries[word]
+	if !ok {
+		return cacheEntry{}, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if entry.expired(c.config.Now()) {
+		c.evict(element, EvictedExpired)
+		return cacheEntry{}, false
+	}
+
+	c.order.MoveToFront(element)
+	return *entry, true
+}
+
+// set adds or replaces a word, evicting the least recently used words if the cache is over capacity.
+// c.mu must be held.
+func (c *CacheDictionary) set(word, definition string, ttl time.Duration) {
+	var expires time.Time
+	if ttl > 0 {
+		expires = c.config.Now().Add(ttl)
+	}
+
+	if element, ok := c.entries[word]; ok {
+		*element.Value.(*cacheEntry) = cacheEntry{word, definition, expires}
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[word] = c.order.PushFront(&cacheEntry{word, definition, expires})
+
+	for c.config.Capacity > 0 && c.order.Len() > c.config.Capacity {
+		c.evict(c.order.Back(), EvictedCapacity)
+	}
+}
+
+func (c *CacheDictionary) evict(element *list.Element, reason EvictionReason) {
+	entry := c.remove(element)
+	c.evicted = append(c.evicted, eviction{entry, reason})
+}
+
+func (c *CacheDictionary) remove(element *list.Element) cacheEntry {
+	entry := c.order.Remove(element).(*cacheEntry)
+	delete(c.entries, entry.word)
+	return *entry
+}
+
+// unlock releases c.mu and then calls OnEvict for anything evicted while it was held.
+// Calling OnEvict without the lock means it's allowed to use the cache itself without deadlocking.
+func (c *CacheDictionary) unlock() {
+	evicted := c.evicted
+	c.evicted = nil
+	c.mu.Unlock()
+
+	if c.config.OnEvict == nil {
+		return
+	}
+	for _, e := range evicted {
+		c.config.OnEvict(e.entry.word, e.entry.definition, e.reason)
+	}
+}
+
+func (e *cacheEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
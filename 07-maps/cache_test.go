@@ -0,0 +1,210 @@
+package dictionary
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCacheDictionary(t *testing.T) {
+	t.Run("entries expire after the TTL", func(t *testing.T) {
+		clock := &stubClock{testTime(0)}
+		spy := &SpyEvictions{}
+		cache := newTestCacheDictionary(t, CacheConfig{TTL: time.Minute, Now: clock.Now, OnEvict: spy.Evict}, Dictionary{"test": "this is just a test"})
+
+		clock.advance(59 * time.Second)
+		assertDefinition(t, cache, "test", "this is just a test")
+
+		clock.advance(time.Second)
+		_, err := cache.Search("test")
+
+		assertError(t, err, ErrNotFound)
+		assertEvictions(t, spy, []string{"test expired"})
+	})
+
+	t.Run("an expired word can be added again", func(t *testing.T) {
+		clock := &stubClock{testTime(0)}
+		cache := newTestCacheDictionary(t, CacheConfig{TTL: time.Minute, Now: clock.Now}, Dictionary{"test": "old"})
+
+		clock.advance(time.Minute)
+
+		assertError(t, cache.Update("test", "new"), ErrWordDoesNotExist)
+		assertError(t, cache.Add("test", "new"), nil)
+		assertDefinition(t, cache, "test", "new")
+	})
+
+	t.Run("per entry TTL", func(t *testing.T) {
+		clock := &stubClock{testTime(0)}
+		cache := NewCacheDictionary(CacheConfig{TTL: time.Minute, Now: clock.Now})
+		assertError(t, cache.AddWithTTL("short", "gone soon", time.Second), nil)
+		assertError(t, cache.AddWithTTL("forever", "never expires", 0), nil)
+		assertError(t, cache.Add("default", "one minute"), nil)
+
+		clock.advance(time.Second)
+		_, err := cache.Search("short")
+		assertError(t, err, ErrNotFound)
+		assertDefinition(t, cache, "default", "one minute")
+
+		clock.advance(time.Hour)
+		_, err = cache.Search("default")
+		assertError(t, err, ErrNotFound)
+		assertDefinition(t, cache, "forever", "never expires")
+	})
+
+	t.Run("update resets the TTL", func(t *testing.T) {
+		clock := &stubClock{testTime(0)}
+		cache := newTestCacheDictionary(t, CacheConfig{TTL: time.Minute, Now: clock.Now}, Dictionary{"test": "old"})
+
+		clock.advance(30 * time.Second)
+		assertError(t, cache.Update("test", "new"), nil)
+		clock.advance(45 * time.Second)
+
+		assertDefinition(t, cache, "test", "new")
+	})
+
+	t.Run("evicts the least recently used word when full", func(t *testing.T) {
+		spy := &SpyEvictions{}
+		cache := NewCacheDictionary(CacheConfig{Capacity: 2, OnEvict: spy.Evict})
+		assertError(t, cache.Add("a", "first"), nil)
+		assertError(t, cache.Add("b", "second"), nil)
+
+		// using "a" makes "b" the least recently used
+		assertDefinition(t, cache, "a", "first")
+		assertError(t, cache.Add("c", "third"), nil)
+
+		_, err := cache.Search("b")
+		assertError(t, err, ErrNotFound)
+		assertDefinition(t, cache, "a", "first")
+		assertDefinition(t, cache, "c", "third")
+		assertEvictions(t, spy, []string{"b full"})
+		if cache.Len() != 2 {
+			t.Errorf("got %d words want 2", cache.Len())
+		}
+	})
+
+	t.Run("delete is not an eviction", func(t *testing.T) {
+		spy := &SpyEvictions{}
+		cache := newTestCacheDictionary(t, CacheConfig{Capacity: 2, OnEvict: spy.Evict}, Dictionary{"test": "this is just a test"})
+
+		assertError(t, cache.Delete("test"), nil)
+
+		assertEvictions(t, spy, nil)
+	})
+
+	t.Run("purge expired", func(t *testing.T) {
+		clock := &stubClock{testTime(0)}
+		spy := &SpyEvictions{}
+		cache := NewCacheDictionary(CacheConfig{TTL: time.Minute, Now: clock.Now, OnEvict: spy.Evict})
+		assertError(t, cache.Add("old", "expires"), nil)
+		assertError(t, cache.AddWithTTL("kept", "never expires", 0), nil)
+
+		clock.advance(time.Minute)
+		cache.PurgeExpired()
+
+		assertEvictions(t, spy, []string{"old expired"})
+		if cache.Len() != 1 {
+			t.Errorf("got %d words want 1", cache.Len())
+		}
+	})
+
+	t.Run("the callback can use the cache", func(t *testing.T) {
+		var cache *CacheDictionary
+		var got []string
+		cache = NewCacheDictionary(CacheConfig{Capacity: 1, OnEvict: func(word, definition string, reason EvictionReason) {
+			// this would deadlock if the callback was called while the cache was locked
+			got = append(got, word)
+			cache.Len()
+		}})
+
+		assertError(t, cache.Add("a", "first"), nil)
+		assertError(t, cache.Add("b", "second"), nil)
+
+		assertWords(t, got, []string{"a"})
+	})
+}
+
+func TestCacheDictionaryLoader(t *testing.T) {
+	t.Run("loads missing words once", func(t *testing.T) {
+		service := &SpyService{words: Dictionary{"test": "this is just a test"}}
+		cache := NewCacheDictionary(CacheConfig{Load: service.Load})
+
+		assertDefinition(t, cache, "test", "this is just a test")
+		assertDefinition(t, cache, "test", "this is just a test")
+
+		if service.calls != 1 {
+			t.Errorf("loaded %d times want 1", service.calls)
+		}
+	})
+
+	t.Run("unknown words are not cached", func(t *testing.T) {
+		service := &SpyService{words: Dictionary{}}
+		cache := NewCacheDictionary(CacheConfig{Load: service.Load})
+
+		_, err := cache.Search("unknown")
+		assertError(t, err, ErrNotFound)
+
+		service.words["unknown"] = "added later"
+		assertDefinition(t, cache, "unknown", "added later")
+	})
+
+	t.Run("expired words are loaded again", func(t *testing.T) {
+		clock := &stubClock{testTime(0)}
+		service := &SpyService{words: Dictionary{"test": "old"}}
+		cache := NewCacheDictionary(CacheConfig{TTL: time.Minute, Now: clock.Now, Load: service.Load})
+
+		assertDefinition(t, cache, "test", "old")
+		service.words["test"] = "new"
+		assertDefinition(t, cache, "test", "old")
+
+		clock.advance(time.Minute)
+		assertDefinition(t, cache, "test", "new")
+	})
+
+	t.Run("loaded words count towards capacity", func(t *testing.T) {
+		service := &SpyService{words: Dictionary{"a": "first", "b": "second"}}
+		spy := &SpyEvictions{}
+		cache := NewCacheDictionary(CacheConfig{Capacity: 1, Load: service.Load, OnEvict: spy.Evict})
+
+		assertDefinition(t, cache, "a", "first")
+		assertDefinition(t, cache, "b", "second")
+
+		assertEvictions(t, spy, []string{"a full"})
+	})
+}
+
+// SpyService is a stand-in for the slow definitions service, counting how often it's asked
+type SpyService struct {
+	words Dictionary
+	calls int
+}
+
+func (s *SpyService) Load(word string) (string, error) {
+	s.calls++
+	return s.words.Search(word)
+}
+
+// SpyEvictions records evictions as "word reason", which is easy to compare
+type SpyEvictions struct {
+	evictions []string
+}
+
+func (s *SpyEvictions) Evict(word, definition string, reason EvictionReason) {
+	reasons := map[EvictionReason]string{EvictedCapacity: "full", EvictedExpired: "expired"}
+	s.evictions = append(s.evictions, word+" "+reasons[reason])
+}
+
+func assertEvictions(t testing.TB, spy *SpyEvictions, want []string) {
+	t.Helper()
+	if !reflect.DeepEqual(spy.evictions, want) {
+		t.Errorf("got evictions %v want %v", spy.evictions, want)
+	}
+}
+
+func newTestCacheDictionary(t testing.TB, config CacheConfig, words Dictionary) *CacheDictionary {
+	t.Helper()
+	cache := NewCacheDictionary(config)
+	for word, definition := range words {
+		assertError(t, cache.Add(word, definition), nil)
+	}
+	return cache
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	dictionary "maps-dictionary"
+)
+
+// A command line tool for the glossary, e.g.
+//
+//	go run ./cmd/dict add banana "a yellow fruit"
+//	go run ./cmd/dict get banana
+//	go run ./cmd/dict -file glossary.log export -format json glossary.json
+//
+// Every command opens the same FileDictionary, so changes made by one are seen by the next.
+// To use the dictionary over HTTP instead, run the server in ../dictserver.
+
+const usage = `usage: dict [-file path] <command> [arguments]
+
+commands:
+  add <word> <definition>
+  get <word>
+  update <word> <definition>
+  delete <word>
+  import [-format csv|tsv|json] [-conflict skip|overwrite|fail] <file>   (- reads from stdin)
+  export [-format csv|tsv|json] [file]                                    (writes to stdout without a file)
+`
+
+var errUsage = errors.New("bad arguments")
+
+var formats = map[string]dictionary.Format{
+	"csv":  dictionary.CSV,
+	"tsv":  dictionary.TSV,
+	"json": dictionary.JSON,
+}
+
+var conflictModes = map[string]dictionary.ConflictMode{
+	"skip":      dictionary.Skip,
+	"overwrite": dictionary.Overwrite,
+	"fail":      dictionary.Fail,
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	if errors.Is(err, errUsage) {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dict:", err)
+		os.Exit(1)
+	}
+}
+
+// run is main without the os package, so it only uses what it's given
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("dict", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	path := flags.String("file", "dictionary.log", "the dictionary log file")
+	if err := flags.Parse(args); err != nil || flags.NArg() == 0 {
+		return errUsage
+	}
+
+	// check the command before opening the dictionary, which creates the log if it isn't there -
+	// a typo like `dict spell banana` shouldn't leave an empty dictionary.log behind
+	act, err := parseCommand(flags.Arg(0), flags.Args()[1:], stdin, stdout)
+	if err != nil {
+		return err
+	}
+
+	d, err := dictionary.OpenFileDictionary(*path)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return act(d)
+}
+
+// an action is a command whose arguments have been checked, ready to run against the dictionary
+type action func(d *dictionary.FileDictionary) error
+
+func parseCommand(command string, args []string, stdin io.Reader, stdout io.Writer) (action, error) {
+	switch command {
+	case "add":
+		if len(args) != 2 {
+			return nil, errUsage
+		}
+		return func(d *dictionary.FileDictionary) error {
+			return d.Add(args[0], args[1])
+		}, nil
+
+	case "get":
+		if len(args) != 1 {
+			return nil, errUsage
+		}
+		return func(d *dictionary.FileDictionary) error {
+			definition, err := d.Search(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(stdout, definition)
+			return nil
+		}, nil
+
+	case "update":
+		if len(args) != 2 {
+			return nil, errUsage
+		}
+		return func(d *dictionary.FileDictionary) error {
+			return d.Update(args[0], args[1])
+		}, nil
+
+	case "delete":
+		if len(args) != 1 {
+			return nil, errUsage
+		}
+		return func(d *dictionary.FileDictionary) error {
+			return d.Delete(args[0])
+		}, nil
+
+	case "import":
+		return importWords(args, stdin, stdout)
+
+	case "export":
+		return exportWords(args, stdout)
+
+	default:
+		return nil, errUsage
+	}
+}
+
+func importWords(args []string, stdin io.Reader, stdout io.Writer) (action, error) {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	formatName := flags.String("format", "csv", "csv, tsv or json")
+	modeName := flags.String("conflict", "skip", "what to do with words that already exist: skip, overwrite or fail")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return nil, errUsage
+	}
+
+	format, ok := formats[strings.ToLower(*formatName)]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", dictionary.ErrUnknownFormat, *formatName)
+	}
+	mode, ok := conflictModes[strings.ToLower(*modeName)]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown conflict mode %q", errUsage, *modeName)
+	}
+
+	return func(d *dictionary.FileDictionary) error {
+		in := stdin
+		if name := flags.Arg(0); name != "-" {
+			file, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			in = file
+		}
+
+		report, err := dictionary.ImportInto(d, in, format, mode)
+		for _, failure := range report.Failed {
+			fmt.Fprintln(stdout, "failed", failure)
+		}
+		fmt.Fprintf(stdout, "added %d, updated %d, skipped %d, failed %d\n",
+			report.Added, report.Updated, report.Skipped, len(report.Failed))
+		return err
+	}, nil
+}
+
+func exportWords(args []string, stdout io.Writer) (action, error) {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	formatName := flags.String("format", "csv", "csv, tsv or json")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 1 {
+		return nil, errUsage
+	}
+
+	format, ok := formats[strings.ToLower(*formatName)]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", dictionary.ErrUnknownFormat, *formatName)
+	}
+
+	return func(d *dictionary.FileDictionary) error {
+		if flags.NArg() == 0 {
+			return d.Words().Export(stdout, format)
+		}
+
+		file, err := os.Create(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		if err := d.Words().Export(file, format); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	}, nil
+}
+
+// you can run this from the 07-maps folder with `go run ./cmd/dict`,
+// and it creates dictionary.log in the current folder unless you pass -file.
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dictionary "maps-dictionary"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("add then get", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.log")
+
+		dict(t, path, "", "add", "banana", "a yellow fruit")
+		got, err := dict(t, path, "", "get", "banana")
+
+		assertNoError(t, err)
+		assertOutput(t, got, "a yellow fruit\n")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.log")
+
+		dict(t, path, "", "add", "banana", "a yellow fruit")
+		_, err := dict(t, path, "", "update", "banana", "a long yellow fruit")
+		assertNoError(t, err)
+
+		got, _ := dict(t, path, "", "get", "banana")
+		assertOutput(t, got, "a long yellow fruit\n")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.log")
+
+		dict(t, path, "", "add", "banana", "a yellow fruit")
+		_, err := dict(t, path, "", "delete", "banana")
+		assertNoError(t, err)
+
+		_, err = dict(t, path, "", "get", "banana")
+		assertError(t, err, dictionary.ErrNotFound)
+	})
+
+	t.Run("errors from the dictionary are returned", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.log")
+
+		_, err := dict(t, path, "", "update", "banana", "a yellow fruit")
+		assertError(t, err, dictionary.ErrWordDoesNotExist)
+
+		dict(t, path, "", "add", "banana", "a yellow fruit")
+		_, err = dict(t, path, "", "add", "banana", "a yellow fruit")
+		assertError(t, err, dictionary.ErrWordExists)
+	})
+
+	t.Run("usage errors", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.log")
+
+		cases := [][]string{
+			{},
+			{"-nonsense"},
+			{"spell", "banana"},
+			{"add", "banana"},
+			{"get"},
+			{"update", "banana"},
+			{"delete", "banana", "apple"},
+			{"import"},
+			{"import", "-conflict", "sometimes", "-"},
+			{"export", "one.csv", "two.csv"},
+		}
+
+		for _, args := range cases {
+			t.Run(strings.Join(args, " "), func(t *testing.T) {
+				_, err := dict(t, path, "", args...)
+				assertError(t, err, errUsage)
+			})
+		}
+
+		// the arguments are checked before the dictionary is opened, so no log is left behind
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("got %v from Stat want the log not to exist", err)
+		}
+	})
+
+	t.Run("import from stdin reports what happened", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.log")
+		dict(t, path, "", "add", "apple", "a red fruit")
+
+		in := "word,definition\napple,a green fruit\nbanana,a yellow fruit\ncherry\n"
+		got, err := dict(t, path, in, "import", "-")
+
+		assertNoError(t, err)
+		assertOutput(t, got, "failed row 4 (\"cherry\"): row should have a word and a definition\n"+
+			"added 1, updated 0, skipped 1, failed 1\n")
+
+		definition, _ := dict(t, path, "", "get", "apple")
+		assertOutput(t, definition, "a red fruit\n")
+	})
+
+	t.Run("import from a file, overwriting", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "dictionary.log")
+		file := filepath.Join(dir, "words.json")
+		writeFile(t, file, `[{"word": "apple", "definition": "a green fruit"}]`)
+		dict(t, path, "", "add", "apple", "a red fruit")
+
+		got, err := dict(t, path, "", "import", "-format", "json", "-conflict", "overwrite", file)
+
+		assertNoError(t, err)
+		assertOutput(t, got, "added 0, updated 1, skipped 0, failed 0\n")
+		definition, _ := dict(t, path, "", "get", "apple")
+		assertOutput(t, definition, "a green fruit\n")
+	})
+
+	t.Run("import with -conflict fail", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.log")
+		dict(t, path, "", "add", "apple", "a red fruit")
+
+		got, err := dict(t, path, "word\tdefinition\nbanana\ta yellow fruit\napple\ta green fruit\n",
+			"import", "-format", "tsv", "-conflict", "fail", "-")
+
+		assertError(t, err, dictionary.ErrWordExists)
+		assertOutput(t, got, "added 0, updated 0, skipped 0, failed 0\n")
+		_, err = dict(t, path, "", "get", "banana")
+		assertError(t, err, dictionary.ErrNotFound)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.log")
+
+		_, err := dict(t, path, "", "import", "-format", "xml", "-")
+		assertError(t, err, dictionary.ErrUnknownFormat)
+
+		_, err = dict(t, path, "", "export", "-format", "xml")
+		assertError(t, err, dictionary.ErrUnknownFormat)
+	})
+
+	t.Run("export to stdout", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.log")
+		dict(t, path, "", "add", "banana", "a yellow fruit")
+		dict(t, path, "", "add", "apple", "a red fruit")
+
+		got, err := dict(t, path, "", "export")
+
+		assertNoError(t, err)
+		assertOutput(t, got, "word,definition\napple,a red fruit\nbanana,a yellow fruit\n")
+	})
+
+	t.Run("export to a file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "dictionary.log")
+		file := filepath.Join(dir, "words.tsv")
+		dict(t, path, "", "add", "banana", "a yellow fruit")
+
+		got, err := dict(t, path, "", "export", "-format", "tsv", file)
+
+		assertNoError(t, err)
+		assertOutput(t, got, "")
+		data, err := os.ReadFile(file)
+		assertNoError(t, err)
+		assertOutput(t, string(data), "word\tdefinition\nbanana\ta yellow fruit\n")
+	})
+}
+
+// dict runs the tool against the log at path, like `dict -file path args...`, and returns what it wrote to stdout
+func dict(t testing.TB, path, stdin string, args ...string) (string, error) {
+	t.Helper()
+	var stdout strings.Builder
+	err := run(append([]string{"-file", path}, args...), strings.NewReader(stdin), &stdout)
+	return stdout.String(), err
+}
+
+func writeFile(t testing.TB, name, data string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertOutput(t testing.TB, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal("didn't expect an error but got one:", err)
+	}
+}
+
+func assertError(t testing.TB, got, want error) {
+	t.Helper()
+	if !errors.Is(got, want) {
+		t.Errorf("got error %v want %v", got, want)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	dictionary "maps-dictionary"
+)
+
+// The JSON API in api.go, for anyone who wants to use the glossary without writing Go, e.g.
+//
+//	curl -X POST -d '{"definition": "a yellow fruit"}' localhost:8080/words/banana
+//	curl localhost:8080/words/banana
+//
+// It uses the same log file as ../dict, so words added with one are there in the other
+// (just not both at once, as each keeps its own copy of the words in memory).
+
+// how long requests that have already started get to finish when we're asked to stop
+const shutdownTimeout = 5 * time.Second
+
+func main() {
+	path := flag.String("file", "dictionary.log", "the dictionary log file")
+	addr := flag.String("addr", ":8080", "the address to listen on")
+	flag.Parse()
+
+	d, err := dictionary.OpenFileDictionary(*path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Close()
+
+	server := &http.Server{Addr: *addr, Handler: dictionary.NewDictionaryServer(d)}
+
+	// ctx is cancelled when we get SIGINT (Ctrl+C)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	go func() {
+		// ListenAndServe always returns an error, ErrServerClosed just means Shutdown was called
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+	log.Printf("serving %s on %s", *path, *addr)
+
+	<-ctx.Done()
+	// stop listening for the signal, so pressing Ctrl+C again quits straight away
+	stop()
+	log.Println("shutting down")
+
+	// Shutdown waits for the open requests to finish, so the log is only closed once nothing is writing to it
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Print(err)
+	}
+}
+
+// you can run this from the 07-maps folder with `go run ./cmd/dictserver`
@@ -1,4 +1,4 @@
-package main
+package dictionary
 
 // a `map` is just a key and a value, similar to an array but indexed by a key.
 // declared as: `map[key type]value type`.
@@ -83,11 +83,18 @@ func (d Dictionary) Update(word, definition string) error {
 }
 
 // adding a delete function to remove a word and it's definitions
-func (d Dictionary) Delete(word string) {
-	// maps have a built-in function `delete`.
-	// `delete` doesn't return anything, and so our Delete function won't either.
-	// deleting a value that doesn't exist has no effect so this is fine.
+// func (d Dictionary) Delete(word string) {
+// 	// maps have a built-in function `delete`.
+// 	// `delete` doesn't return anything, and so our Delete function won't either.
+// 	// deleting a value that doesn't exist has no effect so this is fine.
+// 	delete(d, word)
+// }
+
+// Delete now returns an error so that Dictionary matches the `DictionaryStore` interface (see store.go).
+// Deleting from a map can't fail, so it is always nil here, but deleting from a file can.
+func (d Dictionary) Delete(word string) error {
 	delete(d, word)
+	return nil
 }
 
 // Maps are a bit confusing in the way they work / pointers...
@@ -1,86 +1,119 @@
-package main
+package dictionary
 
 import "testing"
 
+// the same tests are run against every DictionaryStore, so we know the file-backed one
+// behaves exactly like the in-memory Dictionary.
+// Each store is created already holding the given words.
+type newStoreFunc func(t testing.TB, words Dictionary) DictionaryStore
+
+var stores = map[string]newStoreFunc{
+	"in memory": func(t testing.TB, words Dictionary) DictionaryStore {
+		return words
+	},
+	"file": func(t testing.TB, words Dictionary) DictionaryStore {
+		return newTestFileDictionary(t, words)
+	},
+	"cache": func(t testing.TB, words Dictionary) DictionaryStore {
+		return newTestCacheDictionary(t, CacheConfig{}, words)
+	},
+}
+
+func forEachStore(t *testing.T, test func(t *testing.T, newStore newStoreFunc)) {
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			test(t, newStore)
+		})
+	}
+}
+
 func TestSearch(t *testing.T) {
-	dictionary := Dictionary{"test": "this is just a test"}
+	forEachStore(t, func(t *testing.T, newStore newStoreFunc) {
+		dictionary := newStore(t, Dictionary{"test": "this is just a test"})
 
-	t.Run("known word", func(t *testing.T) {
-		got, _ := dictionary.Search("test")
-		want := "this is just a test"
+		t.Run("known word", func(t *testing.T) {
+			got, _ := dictionary.Search("test")
+			want := "this is just a test"
 
-		assertStrings(t, got, want)
-	})
+			assertStrings(t, got, want)
+		})
 
-	t.Run("unknown word", func(t *testing.T) {
-		_, got := dictionary.Search("unknown")
+		t.Run("unknown word", func(t *testing.T) {
+			_, got := dictionary.Search("unknown")
 
-		if got == nil {
-			t.Fatal("expected to get an error.")
-		}
+			if got == nil {
+				t.Fatal("expected to get an error.")
+			}
 
-		assertError(t, got, ErrNotFound)
+			assertError(t, got, ErrNotFound)
+		})
 	})
 }
 
 func TestAdd(t *testing.T) {
-	t.Run("new word", func(t *testing.T) {
-		dictionary := Dictionary{}
-		word := "test"
-		definition := "this is just a test"
-
-		err := dictionary.Add(word, definition)
-
-		assertError(t, err, nil)
-		assertDefinition(t, dictionary, word, definition)
-	})
-
-	// adding a duplicate key to a map will work, but it will overwrite the value
-	// ass the Add function should only add then we must test also for handling duplicate keys
-	t.Run("existing word", func(t *testing.T) {
-		word := "test"
-		definition := "this is just a test"
-		dictionary := Dictionary{word: definition}
-
-		err := dictionary.Add(word, "new test")
-
-		assertError(t, err, ErrWordExists)
-		assertDefinition(t, dictionary, word, definition)
+	forEachStore(t, func(t *testing.T, newStore newStoreFunc) {
+		t.Run("new word", func(t *testing.T) {
+			dictionary := newStore(t, Dictionary{})
+			word := "test"
+			definition := "this is just a test"
+
+			err := dictionary.Add(word, definition)
+
+			assertError(t, err, nil)
+			assertDefinition(t, dictionary, word, definition)
+		})
+
+		// adding a duplicate key to a map will work, but it will overwrite the value
+		// ass the Add function should only add then we must test also for handling duplicate keys
+		t.Run("existing word", func(t *testing.T) {
+			word := "test"
+			definition := "this is just a test"
+			dictionary := newStore(t, Dictionary{word: definition})
+
+			err := dictionary.Add(word, "new test")
+
+			assertError(t, err, ErrWordExists)
+			assertDefinition(t, dictionary, word, definition)
+		})
 	})
 }
 
 func TestUpdate(t *testing.T) {
-	t.Run("existing word", func(t *testing.T) {
-		word := "test"
-		definition := "this is just a test"
-		dictionary := Dictionary{word: definition}
-		newDefinition := "new definition"
+	forEachStore(t, func(t *testing.T, newStore newStoreFunc) {
+		t.Run("existing word", func(t *testing.T) {
+			word := "test"
+			definition := "this is just a test"
+			dictionary := newStore(t, Dictionary{word: definition})
+			newDefinition := "new definition"
 
-		err := dictionary.Update(word, newDefinition)
+			err := dictionary.Update(word, newDefinition)
 
-		assertError(t, err, nil)
-		assertDefinition(t, dictionary, word, newDefinition)
-	})
+			assertError(t, err, nil)
+			assertDefinition(t, dictionary, word, newDefinition)
+		})
 
-	t.Run("new word", func(t *testing.T) {
-		word := "test"
-		definition := "this is just a test"
-		dictionary := Dictionary{}
+		t.Run("new word", func(t *testing.T) {
+			word := "test"
+			definition := "this is just a test"
+			dictionary := newStore(t, Dictionary{})
 
-		err := dictionary.Update(word, definition)
+			err := dictionary.Update(word, definition)
 
-		assertError(t, err, ErrWordDoesNotExist)
+			assertError(t, err, ErrWordDoesNotExist)
+		})
 	})
 }
 
 func TestDelete(t *testing.T) {
-	word := "test"
-	dictionary := Dictionary{word: "test definition"}
+	forEachStore(t, func(t *testing.T, newStore newStoreFunc) {
+		word := "test"
+		dictionary := newStore(t, Dictionary{word: "test definition"})
 
-	dictionary.Delete(word)
+		assertError(t, dictionary.Delete(word), nil)
 
-	_, err := dictionary.Search(word)
-	assertError(t, err, ErrNotFound)
+		_, err := dictionary.Search(word)
+		assertError(t, err, ErrNotFound)
+	})
 }
 
 // helper functions
@@ -101,7 +134,7 @@ func assertError(t testing.TB, got, want error) {
 	}
 }
 
-func assertDefinition(t testing.TB, dictionary Dictionary, word, definition string) {
+func assertDefinition(t testing.TB, dictionary DictionaryStore, word, definition string) {
 	t.Helper()
 
 	got, err := dictionary.Search(word)
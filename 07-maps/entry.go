@@ -0,0 +1,121 @@
+package dictionary
+
+import "slices"
+
+// Words with more than one meaning
+// A Dictionary maps a word to one string, but most words have several "senses" -
+// "bark" is the noise a dog makes *and* the outside of a tree.
+// EntryDictionary keeps a structured Entry for every word, and still has the simple
+// Search/Add/Update/Delete methods, which work on the first definition.
+
+type Sense struct {
+	Definition   string
+	PartOfSpeech string
+	Examples     []string
+	Synonyms     []string
+}
+
+type Entry struct {
+	Senses []Sense
+}
+
+const ErrSenseNotFound = DictionaryErr("could not find that sense of the word")
+
+type EntryDictionary map[string]Entry
+
+// Lookup returns the whole entry for a word.
+// It's a copy, so changing it doesn't change the dictionary - use AddSense and RemoveSense for that.
+func (d EntryDictionary) Lookup(word string) (Entry, error) {
+	entry, ok := d[word]
+	if !ok {
+		return Entry{}, ErrNotFound
+	}
+	return entry.clone(), nil
+}
+
+// AddSense adds another meaning to a word, adding the word if it's new
+func (d EntryDictionary) AddSense(word string, sense Sense) {
+	entry := d[word].clone()
+	entry.Senses = append(entry.Senses, sense.clone())
+	d[word] = entry
+}
+
+// RemoveSense removes the meaning at index (counting from 0).
+// A word with no meanings left is removed from the dictionary.
+func (d EntryDictionary) RemoveSense(word string, index int) error {
+	entry, ok := d[word]
+	if !ok {
+		return ErrWordDoesNotExist
+	}
+
+	if index < 0 || index >= len(entry.Senses) {
+		return ErrSenseNotFound
+	}
+
+	if len(entry.Senses) == 1 {
+		delete(d, word)
+		return nil
+	}
+
+	// slices.Delete would change the array the old entry shares with any copies, so build a new one
+	entry = entry.clone()
+	entry.Senses = slices.Delete(entry.Senses, index, index+1)
+	d[word] = entry
+	return nil
+}
+
+// The methods below are the same as Dictionary's so existing code keeps working.
+
+// Search returns the first definition of a word
+func (d EntryDictionary) Search(word string) (string, error) {
+	entry, ok := d[word]
+	if !ok || len(entry.Senses) == 0 {
+		return "", ErrNotFound
+	}
+	return entry.Senses[0].Definition, nil
+}
+
+func (d EntryDictionary) Add(word, definition string) error {
+	if _, ok := d[word]; ok {
+		return ErrWordExists
+	}
+	d[word] = Entry{Senses: []Sense{{Definition: definition}}}
+	return nil
+}
+
+// Update changes the first definition, leaving the rest of that sense (and the other senses) alone
+func (d EntryDictionary) Update(word, definition string) error {
+	entry, ok := d[word]
+	if !ok {
+		return ErrWordDoesNotExist
+	}
+
+	entry = entry.clone()
+	if len(entry.Senses) == 0 {
+		entry.Senses = []Sense{{}}
+	}
+	entry.Senses[0].Definition = definition
+	d[word] = entry
+	return nil
+}
+
+func (d EntryDictionary) Delete(word string) error {
+	delete(d, word)
+	return nil
+}
+
+// Entries and Senses contain slices, and copying a struct only copies the slice header - both copies
+// would still share the same underlying array. clone makes a copy that shares nothing.
+func (e Entry) clone() Entry {
+	senses := make([]Sense, len(e.Senses))
+	for i, sense := range e.Senses {
+		senses[i] = sense.clone()
+	}
+	return Entry{Senses: senses}
+}
+
+func (s Sense) clone() Sense {
+	s.Examples = slices.Clone(s.Examples)
+	s.Synonyms = slices.Clone(s.Synonyms)
+	return s
+}
@@ -0,0 +1,115 @@
+package dictionary
+
+import (
+	"reflect"
+	"testing"
+)
+
+var (
+	dogBark = Sense{
+		Definition:   "the sound a dog makes",
+		PartOfSpeech: "noun",
+		Examples:     []string{"the dog's bark woke me up"},
+		Synonyms:     []string{"woof", "yap"},
+	}
+	treeBark = Sense{
+		Definition:   "the outer layer of a tree",
+		PartOfSpeech: "noun",
+	}
+	toBark = Sense{
+		Definition:   "to make the sound a dog makes",
+		PartOfSpeech: "verb",
+	}
+)
+
+func TestEntryDictionary(t *testing.T) {
+	t.Run("add and remove senses", func(t *testing.T) {
+		dictionary := EntryDictionary{}
+
+		dictionary.AddSense("bark", dogBark)
+		dictionary.AddSense("bark", treeBark)
+		dictionary.AddSense("bark", toBark)
+		assertEntry(t, dictionary, "bark", Entry{Senses: []Sense{dogBark, treeBark, toBark}})
+
+		assertError(t, dictionary.RemoveSense("bark", 1), nil)
+		assertEntry(t, dictionary, "bark", Entry{Senses: []Sense{dogBark, toBark}})
+	})
+
+	t.Run("remove a sense that doesn't exist", func(t *testing.T) {
+		dictionary := EntryDictionary{"bark": {Senses: []Sense{dogBark}}}
+
+		assertError(t, dictionary.RemoveSense("bark", 1), ErrSenseNotFound)
+		assertError(t, dictionary.RemoveSense("bark", -1), ErrSenseNotFound)
+		assertError(t, dictionary.RemoveSense("meow", 0), ErrWordDoesNotExist)
+	})
+
+	t.Run("removing the last sense removes the word", func(t *testing.T) {
+		dictionary := EntryDictionary{"bark": {Senses: []Sense{dogBark}}}
+
+		assertError(t, dictionary.RemoveSense("bark", 0), nil)
+
+		_, err := dictionary.Lookup("bark")
+		assertError(t, err, ErrNotFound)
+	})
+
+	t.Run("changing a looked up entry doesn't change the dictionary", func(t *testing.T) {
+		dictionary := EntryDictionary{}
+		dictionary.AddSense("bark", dogBark)
+
+		entry, _ := dictionary.Lookup("bark")
+		entry.Senses[0].Synonyms[0] = "changed"
+
+		assertEntry(t, dictionary, "bark", Entry{Senses: []Sense{dogBark}})
+	})
+
+	t.Run("search uses the first definition", func(t *testing.T) {
+		dictionary := EntryDictionary{"bark": {Senses: []Sense{dogBark, treeBark}}}
+
+		got, err := dictionary.Search("bark")
+
+		assertError(t, err, nil)
+		assertStrings(t, got, dogBark.Definition)
+	})
+
+	t.Run("add a simple definition", func(t *testing.T) {
+		dictionary := EntryDictionary{}
+
+		assertError(t, dictionary.Add("test", "this is just a test"), nil)
+		assertError(t, dictionary.Add("test", "new test"), ErrWordExists)
+
+		assertEntry(t, dictionary, "test", Entry{Senses: []Sense{{Definition: "this is just a test"}}})
+	})
+
+	t.Run("update changes only the first definition", func(t *testing.T) {
+		dictionary := EntryDictionary{"bark": {Senses: []Sense{dogBark, treeBark}}}
+
+		assertError(t, dictionary.Update("bark", "a loud noise"), nil)
+		assertError(t, dictionary.Update("meow", "a cat noise"), ErrWordDoesNotExist)
+
+		updated := dogBark
+		updated.Definition = "a loud noise"
+		assertEntry(t, dictionary, "bark", Entry{Senses: []Sense{updated, treeBark}})
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		dictionary := EntryDictionary{"bark": {Senses: []Sense{dogBark}}}
+
+		dictionary.Delete("bark")
+
+		_, err := dictionary.Search("bark")
+		assertError(t, err, ErrNotFound)
+	})
+}
+
+func assertEntry(t testing.TB, dictionary EntryDictionary, word string, want Entry) {
+	t.Helper()
+
+	got, err := dictionary.Lookup(word)
+	if err != nil {
+		t.Fatal("should find word:", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
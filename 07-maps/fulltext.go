@@ -0,0 +1,146 @@
+package dictionary
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Full-text search
+// Sometimes we remember what a word means but not the word. SearchDefinitions finds words by the text of
+// their definitions, using an "inverted index": a map from each term (a word in a definition) to the
+// dictionary words whose definitions contain it. A query then only has to look at the words that share
+// a term with it, rather than reading every definition.
+//
+// Results are ranked with BM25, the usual way search engines score a document (here, a definition) for a query.
+// For each query term it adds up:
+//   - how rare the term is (its "inverse document frequency") - "fruit" tells us more than "used"
+//   - how often it appears in the definition, with each extra time counting for less than the one before
+//   - with less weight for long definitions, which contain more terms just by being long
+
+// the usual BM25 settings: k1 is how quickly repeating a term stops counting for more,
+// b is how much the length of a definition matters (0 not at all, 1 fully)
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// stopWords are too common to say anything about a definition, so they aren't indexed or searched for
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "has": true, "in": true, "is": true, "it": true, "its": true, "of": true,
+	"on": true, "or": true, "that": true, "the": true, "this": true, "to": true, "was": true, "with": true,
+}
+
+type DefinitionMatch struct {
+	Word  string
+	Score float64
+}
+
+type textIndex struct {
+	// postings maps each term to the words whose definition contains it, and how many times
+	postings map[string]map[string]int
+	// lengths is the number of terms in each word's definition, for every indexed word
+	lengths     map[string]int
+	totalLength int
+}
+
+func newTextIndex() *textIndex {
+	return &textIndex{
+		postings: make(map[string]map[string]int),
+		lengths:  make(map[string]int),
+	}
+}
+
+// tokenise splits text into lower case terms, dropping punctuation and stop words.
+// Anything that isn't a letter or a number separates terms, so "don't" is "don" and "t".
+func tokenise(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	terms := fields[:0]
+	for _, field := range fields {
+		if !stopWords[field] {
+			terms = append(terms, field)
+		}
+	}
+	return terms
+}
+
+func (x *textIndex) add(word, definition string) {
+	terms := tokenise(definition)
+	for _, term := range terms {
+		if x.postings[term] == nil {
+			x.postings[term] = make(map[string]int)
+		}
+		x.postings[term][word]++
+	}
+	x.lengths[word] = len(terms)
+	x.totalLength += len(terms)
+}
+
+// remove needs the definition the word was added with, to know which terms to remove it from
+func (x *textIndex) remove(word, definition string) {
+	for _, term := range tokenise(definition) {
+		delete(x.postings[term], word)
+		// don't keep empty maps around for terms nothing uses any more
+		if len(x.postings[term]) == 0 {
+			delete(x.postings, term)
+		}
+	}
+	x.totalLength -= x.lengths[word]
+	delete(x.lengths, word)
+}
+
+// search returns up to limit words ranked by their BM25 score for query, best first.
+// A limit of 0 returns every match.
+func (x *textIndex) search(query string, limit int) []DefinitionMatch {
+	if len(x.lengths) == 0 {
+		return nil
+	}
+
+	documents := float64(len(x.lengths))
+	averageLength := float64(x.totalLength) / documents
+
+	scores := make(map[string]float64)
+	seen := make(map[string]bool)
+	for _, term := range tokenise(query) {
+		// "fruit fruit" shouldn't count twice
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+
+		matches := x.postings[term]
+		if len(matches) == 0 {
+			continue
+		}
+
+		idf := math.Log(1 + (documents-float64(len(matches))+0.5)/(float64(len(matches))+0.5))
+		for word, count := range matches {
+			tf := float64(count)
+			length := float64(x.lengths[word])
+			scores[word] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*length/averageLength))
+		}
+	}
+
+	results := make([]DefinitionMatch, 0, len(scores))
+	for word, score := range scores {
+		results = append(results, DefinitionMatch{word, score})
+	}
+
+	// sort by word when the scores are the same, so the order doesn't depend on the map
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Word < results[j].Word
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
@@ -0,0 +1,133 @@
+package dictionary
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTokenise(t *testing.T) {
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{"A yellow fruit", []string{"yellow", "fruit"}},
+		{"fruit, (usually) YELLOW!", []string{"fruit", "usually", "yellow"}},
+		{"the 3 little pigs", []string{"3", "little", "pigs"}},
+		{"crème brûlée", []string{"crème", "brûlée"}},
+		{"it is the", []string{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.text, func(t *testing.T) {
+			assertWords(t, tokenise(c.text), c.want)
+		})
+	}
+}
+
+func TestSearchDefinitions(t *testing.T) {
+	t.Run("BM25 score", func(t *testing.T) {
+		dictionary := newTestIndexedDictionary(t, Dictionary{"banana": "a yellow fruit", "apple": "a red fruit"})
+
+		got := dictionary.SearchDefinitions("yellow", 0)
+
+		// one of two definitions has "yellow", and both are the average length, so the score is just the idf
+		assertMatches(t, got, []DefinitionMatch{{"banana", math.Log(2)}})
+	})
+
+	t.Run("ranks rare terms and short definitions higher", func(t *testing.T) {
+		dictionary := newTestIndexedDictionary(t, Dictionary{
+			"banana":  "a long curved fruit with yellow skin",
+			"lemon":   "a sour yellow fruit",
+			"apple":   "a round fruit",
+			"custard": "a sweet yellow sauce",
+		})
+
+		assertWordOrder(t, dictionary.SearchDefinitions("yellow", 0), []string{"custard", "lemon", "banana"})
+		// "curved" only matches banana, which makes it worth more than the "fruit" all the others share
+		assertWordOrder(t, dictionary.SearchDefinitions("curved fruit", 0), []string{"banana", "apple", "lemon"})
+	})
+
+	t.Run("query is tokenised the same way", func(t *testing.T) {
+		dictionary := newTestIndexedDictionary(t, Dictionary{"banana": "a yellow fruit"})
+
+		assertWordOrder(t, dictionary.SearchDefinitions("The YELLOW one?", 0), []string{"banana"})
+		assertWordOrder(t, dictionary.SearchDefinitions("the", 0), []string{})
+		assertWordOrder(t, dictionary.SearchDefinitions("", 0), []string{})
+	})
+
+	t.Run("repeating a query term doesn't change the scores", func(t *testing.T) {
+		dictionary := newTestIndexedDictionary(t, Dictionary{"banana": "a yellow fruit", "apple": "a red fruit"})
+
+		assertMatches(t, dictionary.SearchDefinitions("yellow yellow", 0), dictionary.SearchDefinitions("yellow", 0))
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		dictionary := newTestIndexedDictionary(t, Dictionary{"banana": "a yellow fruit", "apple": "a red fruit", "cherry": "a small fruit"})
+
+		if got := dictionary.SearchDefinitions("fruit", 2); len(got) != 2 {
+			t.Errorf("got %d matches want 2", len(got))
+		}
+	})
+
+	t.Run("update replaces the indexed definition", func(t *testing.T) {
+		dictionary := newTestIndexedDictionary(t, Dictionary{"banana": "a yellow fruit"})
+
+		assertError(t, dictionary.Update("banana", "a long curved fruit"), nil)
+
+		assertWordOrder(t, dictionary.SearchDefinitions("yellow", 0), []string{})
+		assertWordOrder(t, dictionary.SearchDefinitions("curved", 0), []string{"banana"})
+	})
+
+	t.Run("failed update changes nothing", func(t *testing.T) {
+		dictionary := newTestIndexedDictionary(t, Dictionary{})
+
+		assertError(t, dictionary.Update("banana", "a yellow fruit"), ErrWordDoesNotExist)
+
+		assertWordOrder(t, dictionary.SearchDefinitions("yellow", 0), []string{})
+	})
+
+	t.Run("delete removes the definition", func(t *testing.T) {
+		dictionary := newTestIndexedDictionary(t, Dictionary{"banana": "a yellow fruit", "apple": "a red fruit"})
+
+		assertError(t, dictionary.Delete("banana"), nil)
+		assertError(t, dictionary.Delete("banana"), nil)
+
+		assertWordOrder(t, dictionary.SearchDefinitions("yellow", 0), []string{})
+		// apple is now the only definition, so the average length has to have been updated too
+		assertMatches(t, dictionary.SearchDefinitions("red", 0), []DefinitionMatch{{"apple", math.Log(1 + 0.5/1.5)}})
+		if len(dictionary.text.postings) != 2 {
+			t.Errorf("got terms %v, want only the terms in apple's definition", dictionary.text.postings)
+		}
+	})
+}
+
+func newTestIndexedDictionary(t testing.TB, words Dictionary) *IndexedDictionary {
+	t.Helper()
+	dictionary := NewIndexedDictionary()
+	for word, definition := range words {
+		assertError(t, dictionary.Add(word, definition), nil)
+	}
+	return dictionary
+}
+
+func assertWordOrder(t testing.TB, matches []DefinitionMatch, want []string) {
+	t.Helper()
+	got := []string{}
+	for _, match := range matches {
+		got = append(got, match.Word)
+	}
+	assertWords(t, got, want)
+}
+
+func assertMatches(t testing.TB, got, want []DefinitionMatch) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %v want %v", got, want)
+	}
+	for i := range got {
+		if got[i].Word != want[i].Word || math.Abs(got[i].Score-want[i].Score) > 1e-9 {
+			t.Errorf("got %v want %v", got, want)
+			return
+		}
+	}
+}
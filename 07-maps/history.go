@@ -0,0 +1,156 @@
+package dictionary
+
+import "time"
+
+// Revision history
+// Update and Delete throw the old definition away. HistoryDictionary wraps any DictionaryStore and
+// remembers every change - who made it, when, and the definition before and after - so changes can be
+// listed, an old revision restored, or the last change undone.
+//
+// Like `ConfigurableSleeper` in the mocking chapter, the clock and the current author are functions
+// passed in, so tests can control them instead of depending on the real time.
+
+type RevisionOp string
+
+const (
+	RevisionAdd    RevisionOp = "add"
+	RevisionUpdate RevisionOp = "update"
+	RevisionDelete RevisionOp = "delete"
+)
+
+type Revision struct {
+	Word   string
+	Op     RevisionOp
+	Author string
+	Time   time.Time
+	// Old is empty for an add, New is empty for a delete
+	Old string
+	New string
+}
+
+const (
+	ErrRevisionNotFound = DictionaryErr("could not find that revision")
+	ErrNothingToUndo    = DictionaryErr("there are no changes to undo")
+)
+
+type HistoryDictionary struct {
+	store   DictionaryStore
+	now     func() time.Time
+	author  func() string
+	history map[string][]Revision
+	// the changes that can still be undone, most recent last
+	undoable []Revision
+}
+
+func NewHistoryDictionary(store DictionaryStore, now func() time.Time, author func() string) *HistoryDictionary {
+	return &HistoryDictionary{
+		store:   store,
+		now:     now,
+		author:  author,
+		history: make(map[string][]Revision),
+	}
+}
+
+func (d *HistoryDictionary) Search(word string) (string, error) {
+	return d.store.Search(word)
+}
+
+func (d *HistoryDictionary) Add(word, definition string) error {
+	return d.change(word, RevisionAdd, definition, true)
+}
+
+func (d *HistoryDictionary) Update(word, definition string) error {
+	return d.change(word, RevisionUpdate, definition, true)
+}
+
+func (d *HistoryDictionary) Delete(word string) error {
+	return d.change(word, RevisionDelete, "", true)
+}
+
+// Revisions lists the changes to a word, oldest first
+func (d *HistoryDictionary) Revisions(word string) []Revision {
+	return append([]Revision(nil), d.history[word]...)
+}
+
+// Restore puts a word back how it was after the given revision (counting from 0, as listed by Revisions).
+// Restoring is itself a change, so it is added to the history and can be undone.
+func (d *HistoryDictionary) Restore(word string, revision int) error {
+	revisions := d.history[word]
+	if revision < 0 || revision >= len(revisions) {
+		return ErrRevisionNotFound
+	}
+	return d.set(word, revisions[revision].Op != RevisionDelete, revisions[revision].New, true)
+}
+
+// Undo reverses the most recent change that hasn't already been undone.
+// The undo is recorded in the word's history, but can't itself be undone.
+func (d *HistoryDictionary) Undo() error {
+	if len(d.undoable) == 0 {
+		return ErrNothingToUndo
+	}
+
+	last := d.undoable[len(d.undoable)-1]
+	if err := d.set(last.Word, last.Op != RevisionAdd, last.Old, false); err != nil {
+		return err
+	}
+
+	d.undoable = d.undoable[:len(d.undoable)-1]
+	return nil
+}
+
+// set makes the word exist with definition, or not exist, using whichever operation gets it there
+func (d *HistoryDictionary) set(word string, exists bool, definition string, undoable bool) error {
+	_, err := d.store.Search(word)
+	currentlyExists := err == nil
+
+	switch {
+	case exists && currentlyExists:
+		return d.change(word, RevisionUpdate, definition, undoable)
+	case exists:
+		return d.change(word, RevisionAdd, definition, undoable)
+	case currentlyExists:
+		return d.change(word, RevisionDelete, "", undoable)
+	default:
+		return nil
+	}
+}
+
+// change makes the change to the store and records it if it worked
+func (d *HistoryDictionary) change(word string, op RevisionOp, definition string, undoable bool) error {
+	old, searchErr := d.store.Search(word)
+	exists := searchErr == nil
+	if !exists {
+		old = ""
+	}
+
+	var err error
+	switch op {
+	case RevisionAdd:
+		err = d.store.Add(word, definition)
+	case RevisionUpdate:
+		err = d.store.Update(word, definition)
+	case RevisionDelete:
+		if !exists {
+			// deleting a word that isn't there changes nothing, so there's nothing to record
+			return nil
+		}
+		err = d.store.Delete(word)
+	}
+	if err != nil {
+		return err
+	}
+
+	revision := Revision{
+		Word:   word,
+		Op:     op,
+		Author: d.author(),
+		Time:   d.now(),
+		Old:    old,
+		New:    definition,
+	}
+	d.history[word] = append(d.history[word], revision)
+	if undoable {
+		d.undoable = append(d.undoable, revision)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package dictionary
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHistoryDictionary(t *testing.T) {
+	t.Run("records who changed what and when", func(t *testing.T) {
+		dictionary, clock, author := newTestHistoryDictionary()
+
+		*author = "adam"
+		assertError(t, dictionary.Add("test", "first"), nil)
+		clock.advance(time.Minute)
+		*author = "chris"
+		assertError(t, dictionary.Update("test", "second"), nil)
+		clock.advance(time.Minute)
+		assertError(t, dictionary.Delete("test"), nil)
+
+		assertRevisions(t, dictionary.Revisions("test"), []Revision{
+			{"test", RevisionAdd, "adam", testTime(0), "", "first"},
+			{"test", RevisionUpdate, "chris", testTime(1), "first", "second"},
+			{"test", RevisionDelete, "chris", testTime(2), "second", ""},
+		})
+	})
+
+	t.Run("failed changes are not recorded", func(t *testing.T) {
+		dictionary, _, _ := newTestHistoryDictionary()
+
+		assertError(t, dictionary.Update("test", "definition"), ErrWordDoesNotExist)
+		assertError(t, dictionary.Delete("test"), nil)
+
+		assertRevisions(t, dictionary.Revisions("test"), nil)
+		assertError(t, dictionary.Undo(), ErrNothingToUndo)
+	})
+
+	t.Run("restore an earlier revision", func(t *testing.T) {
+		dictionary, _, _ := newTestHistoryDictionary()
+		assertError(t, dictionary.Add("test", "first"), nil)
+		assertError(t, dictionary.Update("test", "second"), nil)
+
+		assertError(t, dictionary.Restore("test", 0), nil)
+
+		assertDefinition(t, dictionary, "test", "first")
+		revisions := dictionary.Revisions("test")
+		if got := revisions[len(revisions)-1]; got.Op != RevisionUpdate || got.Old != "second" || got.New != "first" {
+			t.Errorf("restore should be recorded as an update, got %+v", got)
+		}
+	})
+
+	t.Run("restore a deleted word", func(t *testing.T) {
+		dictionary, _, _ := newTestHistoryDictionary()
+		assertError(t, dictionary.Add("test", "first"), nil)
+		assertError(t, dictionary.Delete("test"), nil)
+
+		assertError(t, dictionary.Restore("test", 0), nil)
+
+		assertDefinition(t, dictionary, "test", "first")
+	})
+
+	t.Run("restore a revision that doesn't exist", func(t *testing.T) {
+		dictionary, _, _ := newTestHistoryDictionary()
+		assertError(t, dictionary.Add("test", "first"), nil)
+
+		assertError(t, dictionary.Restore("test", 1), ErrRevisionNotFound)
+		assertError(t, dictionary.Restore("unknown", 0), ErrRevisionNotFound)
+	})
+
+	t.Run("undo goes back one change at a time", func(t *testing.T) {
+		dictionary, _, _ := newTestHistoryDictionary()
+		assertError(t, dictionary.Add("test", "first"), nil)
+		assertError(t, dictionary.Update("test", "second"), nil)
+		assertError(t, dictionary.Delete("test"), nil)
+
+		assertError(t, dictionary.Undo(), nil)
+		assertDefinition(t, dictionary, "test", "second")
+
+		assertError(t, dictionary.Undo(), nil)
+		assertDefinition(t, dictionary, "test", "first")
+
+		assertError(t, dictionary.Undo(), nil)
+		_, err := dictionary.Search("test")
+		assertError(t, err, ErrNotFound)
+
+		assertError(t, dictionary.Undo(), ErrNothingToUndo)
+
+		// three changes and three undos
+		if got := len(dictionary.Revisions("test")); got != 6 {
+			t.Errorf("got %d revisions want 6", got)
+		}
+	})
+
+	t.Run("works with any store", func(t *testing.T) {
+		clock := &stubClock{testTime(0)}
+		dictionary := NewHistoryDictionary(newTestFileDictionary(t, Dictionary{"test": "first"}), clock.Now, func() string { return "adam" })
+
+		assertError(t, dictionary.Update("test", "second"), nil)
+		assertError(t, dictionary.Undo(), nil)
+
+		assertDefinition(t, dictionary, "test", "first")
+	})
+}
+
+// a clock the tests move forward by hand
+type stubClock struct {
+	now time.Time
+}
+
+func (s *stubClock) Now() time.Time {
+	return s.now
+}
+
+func (s *stubClock) advance(d time.Duration) {
+	s.now = s.now.Add(d)
+}
+
+func testTime(minutes int) time.Time {
+	return time.Date(2024, time.October, 20, 9, minutes, 0, 0, time.UTC)
+}
+
+// returns the dictionary along with its clock and author, so tests can change them
+func newTestHistoryDictionary() (*HistoryDictionary, *stubClock, *string) {
+	clock := &stubClock{testTime(0)}
+	author := "adam"
+	dictionary := NewHistoryDictionary(Dictionary{}, clock.Now, func() string { return author })
+	return dictionary, clock, &author
+}
+
+func assertRevisions(t testing.TB, got, want []Revision) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
@@ -0,0 +1,262 @@
+package dictionary
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Importing and exporting
+// Glossaries usually live in spreadsheets, so a Dictionary can be written to and read from CSV, TSV and JSON.
+// Like `Greet` in the dependency injection chapter we use `io.Writer` and `io.Reader`, so it works the same
+// with files, HTTP bodies, or a bytes.Buffer in the tests.
+
+type Format int
+
+const (
+	CSV Format = iota
+	TSV
+	JSON
+)
+
+// ConflictMode says what Import does with a word that's already in the dictionary
+type ConflictMode int
+
+const (
+	// Skip keeps the existing definition
+	Skip ConflictMode = iota
+	// Overwrite replaces it with the imported one
+	Overwrite
+	// Fail imports nothing and returns ErrWordExists
+	Fail
+)
+
+const (
+	ErrUnknownFormat = DictionaryErr("unknown import/export format")
+	ErrEmptyWord     = DictionaryErr("word is empty")
+	ErrBadRow        = DictionaryErr("row should have a word and a definition")
+)
+
+// csv files start with this header row
+var header = []string{"word", "definition"}
+
+// jsonEntry is one item of the JSON array - an array rather than an object keeps the rows in order
+type jsonEntry struct {
+	Word       string `json:"word"`
+	Definition string `json:"definition"`
+}
+
+// RowError says which row of an import failed and why
+type RowError struct {
+	Row  int
+	Word string
+	Err  error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("row %d (%q): %v", e.Row, e.Word, e.Err)
+}
+
+func (e RowError) Unwrap() error {
+	return e.Err
+}
+
+type ImportReport struct {
+	Added   int
+	Updated int
+	Skipped int
+	Failed  []RowError
+}
+
+// Export writes every word in alphabetical order, so exporting the same dictionary always gives the same file
+func (d Dictionary) Export(w io.Writer, format Format) error {
+	words := make([]string, 0, len(d))
+	for word := range d {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	switch format {
+	case CSV, TSV:
+		writer := csv.NewWriter(w)
+		if format == TSV {
+			writer.Comma = '\t'
+		}
+
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		for _, word := range words {
+			if err := writer.Write([]string{word, d[word]}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+
+	case JSON:
+		entries := make([]jsonEntry, len(words))
+		for i, word := range words {
+			entries[i] = jsonEntry{word, d[word]}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// a row read from an import file, before it's added to the dictionary
+type importRow struct {
+	row        int
+	word       string
+	definition string
+}
+
+// Import adds the words from r to the dictionary.
+// Rows that can't be imported (e.g. a missing definition) are listed in the report and the rest are still imported.
+// The returned error is for problems with the whole file - it can't be read, or a word exists when mode is Fail.
+func (d Dictionary) Import(r io.Reader, format Format, mode ConflictMode) (ImportReport, error) {
+	return ImportInto(d, r, format, mode)
+}
+
+// ImportInto is Import for any DictionaryStore, so words can be imported straight into a FileDictionary.
+// A store that can fail part way through (like a full disk) may be left with some of the words.
+func ImportInto(store DictionaryStore, r io.Reader, format Format, mode ConflictMode) (ImportReport, error) {
+	var report ImportReport
+
+	rows, failed, err := readRows(r, format)
+	report.Failed = failed
+	if err != nil {
+		return report, err
+	}
+
+	// check for conflicts before changing anything, so a failed import doesn't leave half the words behind.
+	// A word that's in the file twice is a conflict too, the second one would find the first already added.
+	if mode == Fail {
+		seen := make(map[string]bool, len(rows))
+		for _, row := range rows {
+			if _, err := store.Search(row.word); err == nil || seen[row.word] {
+				return report, RowError{row.row, row.word, ErrWordExists}
+			}
+			seen[row.word] = true
+		}
+	}
+
+	for _, row := range rows {
+		_, err := store.Search(row.word)
+		exists := err == nil
+
+		var count *int
+		switch {
+		case !exists:
+			err, count = store.Add(row.word, row.definition), &report.Added
+		case mode == Overwrite:
+			err, count = store.Update(row.word, row.definition), &report.Updated
+		default:
+			report.Skipped++
+			continue
+		}
+
+		if err != nil {
+			return report, RowError{row.row, row.word, err}
+		}
+		*count++
+	}
+
+	return report, nil
+}
+
+func readRows(r io.Reader, format Format) ([]importRow, []RowError, error) {
+	switch format {
+	case CSV, TSV:
+		return readDelimitedRows(r, format)
+	case JSON:
+		return readJSONRows(r)
+	default:
+		return nil, nil, ErrUnknownFormat
+	}
+}
+
+func readDelimitedRows(r io.Reader, format Format) ([]importRow, []RowError, error) {
+	reader := csv.NewReader(r)
+	if format == TSV {
+		reader.Comma = '\t'
+		// tab separated files from spreadsheets don't usually quote things properly
+		reader.LazyQuotes = true
+	}
+	// we check the number of fields ourselves, so one bad row doesn't stop the import
+	reader.FieldsPerRecord = -1
+
+	var rows []importRow
+	var failed []RowError
+
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			// the reader carries on from the next line after a parse error
+			failed = append(failed, RowError{Row: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		// the header row is optional
+		if first && isHeader(record) {
+			continue
+		}
+
+		if len(record) != 2 {
+			failed = append(failed, RowError{Row: line, Word: record[0], Err: ErrBadRow})
+			continue
+		}
+
+		rows, failed = addRow(rows, failed, importRow{line, record[0], record[1]})
+	}
+
+	return rows, failed, nil
+}
+
+func isHeader(record []string) bool {
+	return len(record) == 2 && strings.EqualFold(record[0], header[0]) && strings.EqualFold(record[1], header[1])
+}
+
+func readJSONRows(r io.Reader) ([]importRow, []RowError, error) {
+	var entries []jsonEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, nil, err
+	}
+
+	var rows []importRow
+	var failed []RowError
+	for i, entry := range entries {
+		rows, failed = addRow(rows, failed, importRow{i + 1, entry.Word, entry.Definition})
+	}
+	return rows, failed, nil
+}
+
+func addRow(rows []importRow, failed []RowError, row importRow) ([]importRow, []RowError) {
+	row.word = strings.TrimSpace(row.word)
+
+	if row.word == "" {
+		return rows, append(failed, RowError{row.row, row.word, ErrEmptyWord})
+	}
+	if strings.TrimSpace(row.definition) == "" {
+		return rows, append(failed, RowError{row.row, row.word, ErrBadRow})
+	}
+	return append(rows, row), failed
+}
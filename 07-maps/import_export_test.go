@@ -0,0 +1,194 @@
+package dictionary
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	dictionary := Dictionary{
+		"test":   "this is just a test",
+		"comma":  "a mark, like this",
+		"banana": "a yellow fruit",
+	}
+
+	cases := []struct {
+		format Format
+		want   string
+	}{
+		{CSV, "word,definition\nbanana,a yellow fruit\ncomma,\"a mark, like this\"\ntest,this is just a test\n"},
+		{TSV, "word\tdefinition\nbanana\ta yellow fruit\ncomma\ta mark, like this\ntest\tthis is just a test\n"},
+		{JSON, `[
+  {
+    "word": "banana",
+    "definition": "a yellow fruit"
+  },
+  {
+    "word": "comma",
+    "definition": "a mark, like this"
+  },
+  {
+    "word": "test",
+    "definition": "this is just a test"
+  }
+]
+`},
+	}
+
+	for _, c := range cases {
+		buffer := &bytes.Buffer{}
+
+		err := dictionary.Export(buffer, c.format)
+
+		assertError(t, err, nil)
+		assertStrings(t, buffer.String(), c.want)
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		assertError(t, dictionary.Export(&bytes.Buffer{}, Format(99)), ErrUnknownFormat)
+	})
+}
+
+func TestImport(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		want := Dictionary{"test": "this is just a test", "comma": "a mark, like this"}
+
+		for _, format := range []Format{CSV, TSV, JSON} {
+			buffer := &bytes.Buffer{}
+			assertError(t, want.Export(buffer, format), nil)
+
+			got := Dictionary{}
+			report, err := got.Import(buffer, format, Fail)
+
+			assertError(t, err, nil)
+			assertReport(t, report, ImportReport{Added: 2})
+			assertDictionary(t, got, want)
+		}
+	})
+
+	t.Run("header is optional", func(t *testing.T) {
+		dictionary := Dictionary{}
+
+		_, err := dictionary.Import(strings.NewReader("test,this is just a test\n"), CSV, Fail)
+
+		assertError(t, err, nil)
+		assertDictionary(t, dictionary, Dictionary{"test": "this is just a test"})
+	})
+
+	t.Run("bad rows are reported and the rest imported", func(t *testing.T) {
+		dictionary := Dictionary{}
+		input := "word,definition\n" +
+			"test,this is just a test\n" +
+			",no word\n" +
+			"no definition,\n" +
+			"too,many,fields\n" +
+			"banana,a yellow fruit\n"
+
+		report, err := dictionary.Import(strings.NewReader(input), CSV, Fail)
+
+		assertError(t, err, nil)
+		assertReport(t, report, ImportReport{Added: 2, Failed: []RowError{
+			{3, "", ErrEmptyWord},
+			{4, "no definition", ErrBadRow},
+			{5, "too", ErrBadRow},
+		}})
+		assertDictionary(t, dictionary, Dictionary{"test": "this is just a test", "banana": "a yellow fruit"})
+	})
+
+	t.Run("badly quoted rows are reported", func(t *testing.T) {
+		dictionary := Dictionary{}
+		input := "bad,\"oops\" quote\ntest,this is just a test\n"
+
+		report, err := dictionary.Import(strings.NewReader(input), CSV, Fail)
+
+		assertError(t, err, nil)
+		if len(report.Failed) != 1 || report.Failed[0].Row != 1 {
+			t.Errorf("got failures %v want one for row 1", report.Failed)
+		}
+		assertDictionary(t, dictionary, Dictionary{"test": "this is just a test"})
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		input := `[{"word": "test", "definition": "new test"}, {"word": "banana", "definition": "a yellow fruit"}]`
+
+		cases := []struct {
+			mode       ConflictMode
+			wantReport ImportReport
+			wantWords  Dictionary
+		}{
+			{Skip, ImportReport{Added: 1, Skipped: 1}, Dictionary{"test": "this is just a test", "banana": "a yellow fruit"}},
+			{Overwrite, ImportReport{Added: 1, Updated: 1}, Dictionary{"test": "new test", "banana": "a yellow fruit"}},
+		}
+
+		for _, c := range cases {
+			dictionary := Dictionary{"test": "this is just a test"}
+
+			report, err := dictionary.Import(strings.NewReader(input), JSON, c.mode)
+
+			assertError(t, err, nil)
+			assertReport(t, report, c.wantReport)
+			assertDictionary(t, dictionary, c.wantWords)
+		}
+	})
+
+	t.Run("fail on conflict imports nothing", func(t *testing.T) {
+		input := `[{"word": "banana", "definition": "a yellow fruit"}, {"word": "test", "definition": "new test"}]`
+		dictionary := Dictionary{"test": "this is just a test"}
+
+		_, err := dictionary.Import(strings.NewReader(input), JSON, Fail)
+
+		if !errors.Is(err, ErrWordExists) {
+			t.Errorf("got %v want %v", err, ErrWordExists)
+		}
+		assertDictionary(t, dictionary, Dictionary{"test": "this is just a test"})
+	})
+
+	t.Run("fail on a word repeated in the file imports nothing", func(t *testing.T) {
+		dictionary := Dictionary{}
+
+		report, err := dictionary.Import(strings.NewReader("test,a\nbanana,a yellow fruit\ntest,b\n"), CSV, Fail)
+
+		assertError(t, err, RowError{3, "test", ErrWordExists})
+		assertReport(t, report, ImportReport{})
+		assertDictionary(t, dictionary, Dictionary{})
+	})
+
+	t.Run("import into any store", func(t *testing.T) {
+		forEachStore(t, func(t *testing.T, newStore newStoreFunc) {
+			store := newStore(t, Dictionary{"test": "this is just a test"})
+			input := "test,new test\nbanana,a yellow fruit\n"
+
+			report, err := ImportInto(store, strings.NewReader(input), CSV, Overwrite)
+
+			assertError(t, err, nil)
+			assertReport(t, report, ImportReport{Added: 1, Updated: 1})
+			assertDefinition(t, store, "test", "new test")
+			assertDefinition(t, store, "banana", "a yellow fruit")
+		})
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := Dictionary{}.Import(strings.NewReader(`{"not": "an array"`), JSON, Fail)
+
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func assertReport(t testing.TB, got, want ImportReport) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got report %+v want %+v", got, want)
+	}
+}
+
+func assertDictionary(t testing.TB, got, want Dictionary) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
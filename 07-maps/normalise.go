@@ -0,0 +1,214 @@
+package dictionary
+
+import (
+	"sort"
+	"unicode"
+)
+
+// Normalised keys
+// "Café", "CAFÉ" and "café" look like the same word to a person, but they are different map keys.
+// Worse, "é" can be written two ways: as one character (U+00E9), or as "e" followed by a
+// combining accent (U+0301). They print the same but are different strings!
+// Unicode defines two "normal forms" to fix this: NFC uses the single character where there is one,
+// and NFD always splits it into the letter and the accent.
+//
+// The standard library doesn't include normalisation (it's in golang.org/x/text), so normalise_tables.go
+// has the data for Latin letters, which covers the words in our glossary.
+
+type NormalForm int
+
+const (
+	// NoForm leaves characters as they are
+	NoForm NormalForm = iota
+	NFC
+	NFD
+)
+
+// KeyNormaliser turns a word into the key it is stored under.
+// Two words with the same key are treated as the same word.
+type KeyNormaliser struct {
+	FoldCase     bool
+	Form         NormalForm
+	StripAccents bool
+}
+
+func (n KeyNormaliser) Normalise(word string) string {
+	if n.Form == NoForm && !n.StripAccents {
+		if n.FoldCase {
+			return string(foldCase([]rune(word)))
+		}
+		return word
+	}
+
+	runes := decompose(word)
+	if n.StripAccents {
+		runes = stripMarks(runes)
+	}
+	if n.FoldCase {
+		runes = foldCase(runes)
+	}
+
+	if n.Form == NFD {
+		return string(runes)
+	}
+	return string(compose(runes))
+}
+
+// upper then lower case means letters with more than one lower case form (like "ſ", a long s) all end up the same
+func foldCase(runes []rune) []rune {
+	folded := make([]rune, len(runes))
+	for i, r := range runes {
+		folded[i] = unicode.ToLower(unicode.ToUpper(r))
+	}
+	return folded
+}
+
+// stripMarks removes combining marks, so after decomposing "é" (e + accent) only "e" is left
+func stripMarks(runes []rune) []rune {
+	var stripped []rune
+	for _, r := range runes {
+		if !unicode.Is(unicode.Mn, r) {
+			stripped = append(stripped, r)
+		}
+	}
+	return stripped
+}
+
+// decompose gives the NFD form of word: every character split into its base and marks,
+// with the marks after each base sorted by their combining class
+func decompose(word string) []rune {
+	var runes []rune
+	for _, r := range word {
+		runes = appendDecomposed(runes, r)
+	}
+
+	// sort each run of marks; SliceStable keeps marks with the same class in their original order
+	for start := 0; start < len(runes); {
+		if combiningClasses[runes[start]] == 0 {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && combiningClasses[runes[end]] != 0 {
+			end++
+		}
+		marks := runes[start:end]
+		sort.SliceStable(marks, func(i, j int) bool {
+			return combiningClasses[marks[i]] < combiningClasses[marks[j]]
+		})
+		start = end
+	}
+
+	return runes
+}
+
+func appendDecomposed(runes []rune, r rune) []rune {
+	parts, ok := decompositions[r]
+	if !ok {
+		return append(runes, r)
+	}
+	runes = appendDecomposed(runes, parts[0])
+	return append(runes, parts[1])
+}
+
+// compositions is decompositions the other way round: base and mark to the single character
+var compositions = func() map[[2]rune]rune {
+	c := make(map[[2]rune]rune, len(decompositions))
+	for composed, parts := range decompositions {
+		c[parts] = composed
+	}
+	return c
+}()
+
+// compose turns decomposed runes into the NFC form, joining each base with the marks after it where possible.
+// A mark can't join its base if there's another mark in between with the same or a higher combining class
+// (Unicode calls it "blocked").
+func compose(runes []rune) []rune {
+	var composed []rune
+	starter := -1
+	// the class of the last mark added since the starter, or -1 if there isn't one
+	lastClass := -1
+
+	for _, r := range runes {
+		class := int(combiningClasses[r])
+
+		if starter >= 0 && (lastClass == -1 || lastClass < class) {
+			if joined, ok := compositions[[2]rune{composed[starter], r}]; ok {
+				composed[starter] = joined
+				continue
+			}
+		}
+
+		if class == 0 {
+			starter = len(composed)
+			lastClass = -1
+		} else {
+			lastClass = class
+		}
+		composed = append(composed, r)
+	}
+
+	return composed
+}
+
+// NormalisedDictionary looks words up by their normalised key, but remembers how each word was
+// spelled when it was added, so it can be shown to people the way they wrote it.
+type NormalisedDictionary struct {
+	normaliser KeyNormaliser
+	entries    map[string]normalisedEntry
+}
+
+type normalisedEntry struct {
+	spelling   string
+	definition string
+}
+
+func NewNormalisedDictionary(normaliser KeyNormaliser) *NormalisedDictionary {
+	return &NormalisedDictionary{
+		normaliser: normaliser,
+		entries:    make(map[string]normalisedEntry),
+	}
+}
+
+func (d *NormalisedDictionary) Search(word string) (string, error) {
+	entry, ok := d.entries[d.normaliser.Normalise(word)]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return entry.definition, nil
+}
+
+// Spelling returns the word the way it was spelled when it was added
+func (d *NormalisedDictionary) Spelling(word string) (string, error) {
+	entry, ok := d.entries[d.normaliser.Normalise(word)]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return entry.spelling, nil
+}
+
+func (d *NormalisedDictionary) Add(word, definition string) error {
+	key := d.normaliser.Normalise(word)
+	if _, ok := d.entries[key]; ok {
+		return ErrWordExists
+	}
+	d.entries[key] = normalisedEntry{word, definition}
+	return nil
+}
+
+// Update keeps the original spelling, however the word is written this time
+func (d *NormalisedDictionary) Update(word, definition string) error {
+	key := d.normaliser.Normalise(word)
+	entry, ok := d.entries[key]
+	if !ok {
+		return ErrWordDoesNotExist
+	}
+	entry.definition = definition
+	d.entries[key] = entry
+	return nil
+}
+
+func (d *NormalisedDictionary) Delete(word string) error {
+	delete(d.entries, d.normaliser.Normalise(word))
+	return nil
+}
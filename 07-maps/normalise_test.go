@@ -0,0 +1,86 @@
+package dictionary
+
+import "testing"
+
+// combining marks are written as `\u` escapes, otherwise "cafe\u0301" and "café" look identical in the source
+
+func TestKeyNormaliser(t *testing.T) {
+	cases := []struct {
+		name       string
+		normaliser KeyNormaliser
+		word       string
+		want       string
+	}{
+		{"no normalisation", KeyNormaliser{}, "Cafe\u0301", "Cafe\u0301"},
+		{"NFC joins letter and accent", KeyNormaliser{Form: NFC}, "cafe\u0301", "café"},
+		{"NFC leaves composed letters", KeyNormaliser{Form: NFC}, "café", "café"},
+		{"NFD splits letter and accent", KeyNormaliser{Form: NFD}, "café", "cafe\u0301"},
+		{"NFD splits more than once", KeyNormaliser{Form: NFD}, "ǖ", "u\u0308\u0304"},
+		{"NFC joins more than once", KeyNormaliser{Form: NFC}, "u\u0308\u0304", "ǖ"},
+		{"marks are put in order", KeyNormaliser{Form: NFD}, "a\u0302\u0323", "a\u0323\u0302"},
+		{"NFC in any mark order", KeyNormaliser{Form: NFC}, "a\u0302\u0323", "ậ"},
+		{"blocked marks stay separate", KeyNormaliser{Form: NFC}, "e\u0301\u0301", "é\u0301"},
+		{"fold case", KeyNormaliser{FoldCase: true}, "CAFÉ", "café"},
+		{"fold case and NFC", KeyNormaliser{FoldCase: true, Form: NFC}, "CAFE\u0301", "café"},
+		{"strip accents", KeyNormaliser{StripAccents: true}, "Crème Brûlée", "Creme Brulee"},
+		{"strip combining accents", KeyNormaliser{StripAccents: true}, "cafe\u0301", "cafe"},
+		{"everything", KeyNormaliser{FoldCase: true, Form: NFC, StripAccents: true}, "ÇA\u0300 ET LÀ", "ca et la"},
+		{"characters without a table entry are unchanged", KeyNormaliser{Form: NFD}, "日本 ﬁ", "日本 ﬁ"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertStrings(t, c.normaliser.Normalise(c.word), c.want)
+		})
+	}
+}
+
+func TestNormalisedDictionary(t *testing.T) {
+	normaliser := KeyNormaliser{FoldCase: true, Form: NFC}
+
+	t.Run("different ways of writing a word find the same entry", func(t *testing.T) {
+		dictionary := NewNormalisedDictionary(normaliser)
+		assertError(t, dictionary.Add("Café", "a place to get coffee"), nil)
+
+		for _, word := range []string{"Café", "cafe\u0301", "CAFÉ", "CAFE\u0301"} {
+			got, err := dictionary.Search(word)
+			assertError(t, err, nil)
+			assertStrings(t, got, "a place to get coffee")
+		}
+
+		assertError(t, dictionary.Add("CAFE\u0301", "again"), ErrWordExists)
+	})
+
+	t.Run("keeps the original spelling", func(t *testing.T) {
+		dictionary := NewNormalisedDictionary(normaliser)
+		assertError(t, dictionary.Add("Café", "a place to get coffee"), nil)
+
+		assertError(t, dictionary.Update("CAFÉ", "a small restaurant"), nil)
+
+		got, err := dictionary.Spelling("cafe\u0301")
+		assertError(t, err, nil)
+		assertStrings(t, got, "Café")
+		assertDefinition(t, dictionary, "café", "a small restaurant")
+	})
+
+	t.Run("update and delete use the normalised key", func(t *testing.T) {
+		dictionary := NewNormalisedDictionary(normaliser)
+
+		assertError(t, dictionary.Update("café", "definition"), ErrWordDoesNotExist)
+
+		assertError(t, dictionary.Add("café", "definition"), nil)
+		assertError(t, dictionary.Delete("CAFE\u0301"), nil)
+
+		_, err := dictionary.Search("café")
+		assertError(t, err, ErrNotFound)
+		_, err = dictionary.Spelling("café")
+		assertError(t, err, ErrNotFound)
+	})
+
+	t.Run("accents can be ignored", func(t *testing.T) {
+		dictionary := NewNormalisedDictionary(KeyNormaliser{FoldCase: true, StripAccents: true})
+		assertError(t, dictionary.Add("Café", "a place to get coffee"), nil)
+
+		assertDefinition(t, dictionary, "cafe", "a place to get coffee")
+	})
+}
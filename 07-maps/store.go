@@ -0,0 +1,281 @@
+package dictionary
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DictionaryStore is anything that stores words the way Dictionary does.
+// Dictionary (in memory) and FileDictionary (on disk) both satisfy it,
+// so code that only needs these methods can be given either.
+type DictionaryStore interface {
+	Search(word string) (string, error)
+	Add(word, definition string) error
+	Update(word, definition string) error
+	Delete(word string) error
+}
+
+// FileDictionary is a Dictionary that survives restarts.
+//
+// Every change is appended to a log file as one JSON line, and flushed to disk before the method returns.
+// On start-up the log is replayed into an ordinary Dictionary, which is then used for lookups.
+// Updates and deletes leave old lines behind, so once most of the log is out of date it is "compacted":
+// rewritten with just one line per word.
+//
+// If the program crashes while writing a line, the log ends with a torn record. That change was
+// never reported as saved, so when the log is opened again the torn record is cut off.
+type FileDictionary struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	words   Dictionary
+	records int
+}
+
+const ErrCorruptLog = DictionaryErr("dictionary log is corrupt")
+
+// don't bother compacting small logs
+const compactMinRecords = 100
+
+const (
+	opSet    = "set"
+	opDelete = "delete"
+)
+
+type logRecord struct {
+	Op         string `json:"op"`
+	Word       string `json:"word"`
+	Definition string `json:"definition,omitempty"`
+}
+
+func OpenFileDictionary(path string) (*FileDictionary, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	// if the log was just created it's only on disk once the directory is synced,
+	// otherwise a crash could lose the whole file along with the words Add said were saved
+	if err := syncDir(filepath.Dir(path)); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	d := &FileDictionary{path: path, file: file, words: Dictionary{}}
+	if err := d.replay(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *FileDictionary) replay() error {
+	reader := bufio.NewReader(d.file)
+	var offset int64
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// a last line without a newline was never finished
+			if len(line) > 0 {
+				return d.truncate(offset)
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		var record logRecord
+		if err := json.Unmarshal(bytes.TrimSpace(line), &record); err != nil {
+			// a bad line can only be a torn write if it's the last one
+			if _, peekErr := reader.Peek(1); peekErr == io.EOF {
+				return d.truncate(offset)
+			}
+			return fmt.Errorf("%w: line at byte %d: %v", ErrCorruptLog, offset, err)
+		}
+
+		switch record.Op {
+		case opSet:
+			d.words[record.Word] = record.Definition
+		case opDelete:
+			delete(d.words, record.Word)
+		default:
+			return fmt.Errorf("%w: unknown operation %q", ErrCorruptLog, record.Op)
+		}
+
+		offset += int64(len(line))
+		d.records++
+	}
+
+	_, err := d.file.Seek(offset, io.SeekStart)
+	return err
+}
+
+func (d *FileDictionary) truncate(offset int64) error {
+	if err := d.file.Truncate(offset); err != nil {
+		return err
+	}
+	if err := d.file.Sync(); err != nil {
+		return err
+	}
+	_, err := d.file.Seek(offset, io.SeekStart)
+	return err
+}
+
+func (d *FileDictionary) Search(word string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.words.Search(word)
+}
+
+func (d *FileDictionary) Add(word, definition string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.words.Search(word); err == nil {
+		return ErrWordExists
+	}
+	return d.write(logRecord{opSet, word, definition})
+}
+
+func (d *FileDictionary) Update(word, definition string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.words.Search(word); err != nil {
+		return ErrWordDoesNotExist
+	}
+	return d.write(logRecord{opSet, word, definition})
+}
+
+func (d *FileDictionary) Delete(word string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// deleting a word that doesn't exist has no effect, so there's nothing to save
+	if _, err := d.words.Search(word); err != nil {
+		return nil
+	}
+	return d.write(logRecord{Op: opDelete, Word: word})
+}
+
+// write saves the record, then applies it to the in-memory words. d.mu must be held.
+func (d *FileDictionary) write(record logRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	offset, err := d.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	// if the write fails part way, cut the partial line off so the next one starts cleanly
+	if _, err := d.file.Write(append(line, '\n')); err != nil {
+		d.truncate(offset)
+		return err
+	}
+	if err := d.file.Sync(); err != nil {
+		d.truncate(offset)
+		return err
+	}
+
+	switch record.Op {
+	case opSet:
+		d.words[record.Word] = record.Definition
+	case opDelete:
+		delete(d.words, record.Word)
+	}
+	d.records++
+
+	// the change is already saved, so a failed compaction isn't an error for the caller -
+	// it will be tried again on the next write
+	if d.records >= compactMinRecords && d.records > 2*len(d.words) {
+		d.compact() //nolint:errcheck
+	}
+	return nil
+}
+
+// Compact rewrites the log with one line per word
+func (d *FileDictionary) Compact() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.compact()
+}
+
+func (d *FileDictionary) compact() error {
+	tmpPath := d.path + ".compact"
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	for word, definition := range d.words {
+		if err := encoder.Encode(logRecord{opSet, word, definition}); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	// renaming replaces the old log in one step, so we either have the old log or the new one, never half of each
+	if err := os.Rename(tmpPath, d.path); err != nil {
+		tmp.Close()
+		return err
+	}
+	// the rename is a change to the directory, and is only on disk once the directory is synced.
+	// Without this a crash could bring back the old log, losing every write made to the new one.
+	syncErr := syncDir(filepath.Dir(d.path))
+
+	// tmp is positioned at its end, ready for the next write. We switch to it even if the sync failed,
+	// as the old file isn't in the directory any more and anything written to it would be lost for sure.
+	d.file.Close()
+	d.file = tmp
+	d.records = len(d.words)
+	return syncErr
+}
+
+// syncDir flushes a directory's entries (which files it has, and their names) to disk
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (d *FileDictionary) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.file.Close()
+}
+
+// Words returns a copy of every word, e.g. for exporting.
+// It's a copy so the caller can't change the words without them being saved.
+func (d *FileDictionary) Words() Dictionary {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return maps.Clone(d.words)
+}
@@ -0,0 +1,136 @@
+package dictionary
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileDictionary(t *testing.T) {
+	t.Run("survives a restart", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.log")
+		dictionary := openTestFileDictionary(t, path)
+		assertError(t, dictionary.Add("test", "this is just a test"), nil)
+		assertError(t, dictionary.Add("banana", "a yellow fruit"), nil)
+		assertError(t, dictionary.Update("test", "new definition"), nil)
+		assertError(t, dictionary.Delete("banana"), nil)
+		dictionary.Close()
+
+		reopened := openTestFileDictionary(t, path)
+
+		assertDefinition(t, reopened, "test", "new definition")
+		_, err := reopened.Search("banana")
+		assertError(t, err, ErrNotFound)
+	})
+
+	t.Run("recovers from a torn final record", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.log")
+		dictionary := openTestFileDictionary(t, path)
+		assertError(t, dictionary.Add("test", "this is just a test"), nil)
+		dictionary.Close()
+
+		// simulate a crash half way through writing a record
+		appendToFile(t, path, `{"op":"set","word":"banana","defin`)
+
+		reopened := openTestFileDictionary(t, path)
+		assertDefinition(t, reopened, "test", "this is just a test")
+
+		// the torn record is gone, so the next record starts on a clean line
+		assertError(t, reopened.Add("banana", "a yellow fruit"), nil)
+		reopened.Close()
+
+		assertDefinition(t, openTestFileDictionary(t, path), "banana", "a yellow fruit")
+	})
+
+	t.Run("refuses a log corrupted in the middle", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.log")
+		appendToFile(t, path, "not json\n"+`{"op":"set","word":"test","definition":"a test"}`+"\n")
+
+		_, err := OpenFileDictionary(path)
+
+		if !errors.Is(err, ErrCorruptLog) {
+			t.Errorf("got %v want %v", err, ErrCorruptLog)
+		}
+	})
+
+	t.Run("compacts a log of mostly old records", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.log")
+		dictionary := openTestFileDictionary(t, path)
+		assertError(t, dictionary.Add("test", "definition 0"), nil)
+		for i := 1; i <= compactMinRecords; i++ {
+			assertError(t, dictionary.Update("test", "definition "+strings.Repeat("!", i)), nil)
+		}
+
+		if lines := countLines(t, path); lines >= compactMinRecords {
+			t.Errorf("got %d lines, expected the log to have been compacted", lines)
+		}
+
+		// still works after compacting, and after a restart
+		assertError(t, dictionary.Add("banana", "a yellow fruit"), nil)
+		dictionary.Close()
+
+		reopened := openTestFileDictionary(t, path)
+		assertDefinition(t, reopened, "test", "definition "+strings.Repeat("!", compactMinRecords))
+		assertDefinition(t, reopened, "banana", "a yellow fruit")
+	})
+
+	t.Run("compact on demand", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.log")
+		dictionary := openTestFileDictionary(t, path)
+		assertError(t, dictionary.Add("test", "this is just a test"), nil)
+		assertError(t, dictionary.Update("test", "new definition"), nil)
+		assertError(t, dictionary.Add("banana", "a yellow fruit"), nil)
+		assertError(t, dictionary.Delete("banana"), nil)
+
+		assertError(t, dictionary.Compact(), nil)
+
+		if lines := countLines(t, path); lines != 1 {
+			t.Errorf("got %d lines want 1", lines)
+		}
+		assertDefinition(t, dictionary, "test", "new definition")
+	})
+}
+
+// newTestFileDictionary makes a FileDictionary in a temporary directory holding the given words
+func newTestFileDictionary(t testing.TB, words Dictionary) *FileDictionary {
+	t.Helper()
+	dictionary := openTestFileDictionary(t, filepath.Join(t.TempDir(), "dictionary.log"))
+	for word, definition := range words {
+		assertError(t, dictionary.Add(word, definition), nil)
+	}
+	return dictionary
+}
+
+func openTestFileDictionary(t testing.TB, path string) *FileDictionary {
+	t.Helper()
+	dictionary, err := OpenFileDictionary(path)
+	if err != nil {
+		t.Fatal("could not open dictionary:", err)
+	}
+	// closing twice just returns an error we don't care about
+	t.Cleanup(func() { dictionary.Close() })
+	return dictionary
+}
+
+func appendToFile(t testing.TB, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func countLines(t testing.TB, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "\n")
+}
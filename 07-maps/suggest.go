@@ -0,0 +1,221 @@
+package dictionary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// "Did you mean ...?"
+// When a search fails, the user has usually made a typo. The "edit distance" between two words is how many
+// single letter changes (insert, delete, substitute, or swap two neighbouring letters) turn one into the other,
+// e.g. "tset" -> "test" is 1 (a swap). Words a small distance away make good suggestions.
+//
+// Working out the distance to *every* word in the dictionary would be slow, so the words are kept in a
+// BK-tree, which lets us skip most of them (see `search`).
+
+const (
+	maxSuggestionDistance = 2
+	maxSuggestions        = 3
+)
+
+// NotFoundError is returned instead of plain ErrNotFound when we have suggestions.
+// `errors.Is(err, ErrNotFound)` still works because Unwrap returns ErrNotFound.
+type NotFoundError struct {
+	Word        string
+	Suggestions []string
+}
+
+func (e *NotFoundError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return ErrNotFound.Error()
+	}
+	return fmt.Sprintf("%s, did you mean %s?", ErrNotFound, strings.Join(e.Suggestions, ", "))
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return ErrNotFound
+}
+
+// editDistance is the Damerau-Levenshtein distance.
+// It works on runes rather than bytes, so "café" -> "cafe" is one change, not two.
+//
+// There is a simpler "optimal string alignment" version that only needs the previous two rows, but it can give
+// a longer distance than going via another word, which would break the BK-tree (see below), so we use the full one.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	infinity := len(s) + len(t)
+
+	// d is a (len(s)+2) x (len(t)+2) table stored in one slice; d[(i+1)*width+(j+1)] is the distance between
+	// the first i runes of s and the first j runes of t. The extra first row and column hold "infinity".
+	// This gets called a lot, so for normal length words use an array that doesn't need allocating.
+	width := len(t) + 2
+	var small [256]int
+	var d []int
+	if size := (len(s) + 2) * width; size <= len(small) {
+		d = small[:size]
+	} else {
+		d = make([]int, size)
+	}
+
+	d[0] = infinity
+	for i := 0; i <= len(s); i++ {
+		d[(i+1)*width] = infinity
+		d[(i+1)*width+1] = i
+	}
+	for j := 0; j <= len(t); j++ {
+		d[j+1] = infinity
+		d[width+j+1] = j
+	}
+
+	// lastRow(r) is the last row of s that rune r was seen in.
+	// Most words are ASCII so those go in an array, which is much quicker than a map.
+	var asciiRows [utf8.RuneSelf]int
+	var otherRows map[rune]int
+	lastRow := func(r rune) int {
+		if r < utf8.RuneSelf {
+			return asciiRows[r]
+		}
+		return otherRows[r]
+	}
+
+	for i := 1; i <= len(s); i++ {
+		// the last column of t that matched s[i-1]
+		lastMatchCol := 0
+		for j := 1; j <= len(t); j++ {
+			k := lastRow(t[j-1])
+			l := lastMatchCol
+
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+				lastMatchCol = j
+			}
+
+			d[(i+1)*width+j+1] = min(
+				d[i*width+j+1]+1,               // delete
+				d[(i+1)*width+j]+1,             // insert
+				d[i*width+j]+cost,              // substitute
+				d[k*width+l]+(i-k-1)+1+(j-l-1), // swap, with anything in between deleted or inserted
+			)
+		}
+
+		if r := s[i-1]; r < utf8.RuneSelf {
+			asciiRows[r] = i
+		} else {
+			if otherRows == nil {
+				otherRows = make(map[rune]int)
+			}
+			otherRows[r] = i
+		}
+	}
+
+	return d[(len(s)+1)*width+len(t)+1]
+}
+
+// A BK-tree node's children are keyed by their distance from it.
+// Because edit distance obeys the triangle inequality, if we are looking for words within `maxDistance` of `word`,
+// and this node is `dist` away, only children between dist-maxDistance and dist+maxDistance can possibly match.
+type bkNode struct {
+	word string
+	// removing a node would mean rebuilding everything under it, so deleted words are just marked
+	deleted bool
+	// children[dist] is the child that is dist away, or nil. Distances are small so a slice is fine.
+	children []*bkNode
+}
+
+type bkTree struct {
+	root *bkNode
+}
+
+func (t *bkTree) insert(word string) {
+	if t.root == nil {
+		t.root = &bkNode{word: word}
+		return
+	}
+
+	node := t.root
+	for {
+		dist := editDistance(word, node.word)
+		if dist == 0 {
+			node.deleted = false
+			return
+		}
+
+		if dist >= len(node.children) {
+			node.children = append(node.children, make([]*bkNode, dist+1-len(node.children))...)
+		}
+
+		if node.children[dist] == nil {
+			node.children[dist] = &bkNode{word: word}
+			return
+		}
+		node = node.children[dist]
+	}
+}
+
+func (t *bkTree) remove(word string) {
+	node := t.root
+	for node != nil {
+		dist := editDistance(word, node.word)
+		if dist == 0 {
+			node.deleted = true
+			return
+		}
+		// no child at that distance, so the word isn't in the tree
+		if dist >= len(node.children) {
+			return
+		}
+		node = node.children[dist]
+	}
+}
+
+type suggestion struct {
+	word     string
+	distance int
+}
+
+// search returns words within maxDistance of word, closest first (and alphabetically for the same distance)
+func (t *bkTree) search(word string, maxDistance int) []suggestion {
+	var found []suggestion
+
+	if t.root != nil {
+		stack := []*bkNode{t.root}
+		for len(stack) > 0 {
+			node := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			dist := editDistance(word, node.word)
+			if dist <= maxDistance && !node.deleted {
+				found = append(found, suggestion{node.word, dist})
+			}
+
+			for childDist := max(dist-maxDistance, 1); childDist <= dist+maxDistance && childDist < len(node.children); childDist++ {
+				if child := node.children[childDist]; child != nil {
+					stack = append(stack, child)
+				}
+			}
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].distance != found[j].distance {
+			return found[i].distance < found[j].distance
+		}
+		return found[i].word < found[j].word
+	})
+	return found
+}
+
+// Suggest returns up to maxSuggestions words close to the given (probably misspelt) word
+func (d *IndexedDictionary) Suggest(word string) []string {
+	var words []string
+	for _, s := range d.fuzzy.search(word, maxSuggestionDistance) {
+		if len(words) == maxSuggestions {
+			break
+		}
+		words = append(words, s.word)
+	}
+	return words
+}
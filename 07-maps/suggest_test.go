@@ -0,0 +1,143 @@
+package dictionary
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"test", "test", 0},
+		{"test", "tent", 1},
+		{"test", "tests", 1},
+		{"test", "tst", 1},
+		{"test", "tset", 1},
+		{"café", "cafe", 1},
+		{"kitten", "sitting", 3},
+		{"ca", "abc", 2},
+		{"", "abc", 3},
+	}
+
+	for _, c := range cases {
+		t.Run(c.a+" to "+c.b, func(t *testing.T) {
+			if got := editDistance(c.a, c.b); got != c.want {
+				t.Errorf("got %d want %d", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	dictionary := NewIndexedDictionary()
+	for _, word := range []string{"test", "tent", "text", "toast", "banana", "tests"} {
+		assertError(t, dictionary.Add(word, "definition of "+word), nil)
+	}
+
+	t.Run("not found error carries the closest matches", func(t *testing.T) {
+		_, err := dictionary.Search("tset")
+
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("got %v want it to be %v", err, ErrNotFound)
+		}
+
+		var notFound *NotFoundError
+		if !errors.As(err, &notFound) {
+			t.Fatalf("got %T want *NotFoundError", err)
+		}
+
+		assertStrings(t, notFound.Word, "tset")
+		// "test" is one swap away, then the others two changes away in alphabetical order
+		assertWords(t, notFound.Suggestions, []string{"test", "tent", "tests"})
+		assertStrings(t, err.Error(), "could not find the word you were looking for, did you mean test, tent, tests?")
+	})
+
+	t.Run("nothing close", func(t *testing.T) {
+		_, err := dictionary.Search("xylophone")
+
+		var notFound *NotFoundError
+		errors.As(err, &notFound)
+
+		assertWords(t, notFound.Suggestions, nil)
+		assertStrings(t, err.Error(), ErrNotFound.Error())
+	})
+
+	t.Run("deleted words are not suggested", func(t *testing.T) {
+		dictionary := NewIndexedDictionary()
+		assertError(t, dictionary.Add("test", "a test"), nil)
+
+		dictionary.Delete("test")
+		assertWords(t, dictionary.Suggest("tset"), nil)
+
+		// adding it back brings the suggestion back
+		assertError(t, dictionary.Add("test", "a test"), nil)
+		assertWords(t, dictionary.Suggest("tset"), []string{"test"})
+	})
+
+	t.Run("removing a word that isn't in the tree", func(t *testing.T) {
+		tree := &bkTree{}
+		tree.insert("a")
+
+		// "zzzzzzzz" is further from "a" than any child "a" has, which used to index past the end of its children
+		tree.remove("zzzzzzzz")
+
+		if got := tree.search("a", 0); len(got) != 1 || got[0].word != "a" {
+			t.Errorf("got %v want just a", got)
+		}
+	})
+}
+
+// the BK-tree skips words, so check it doesn't skip any it shouldn't by comparing it to looking at every word
+func TestBKTreeFindsEveryCloseWord(t *testing.T) {
+	corpus := benchmarkCorpus()[:5000]
+	tree := &bkTree{}
+	for _, word := range corpus {
+		tree.insert(word)
+	}
+
+	for _, query := range []string{"abcdef", "test", "zzz", "qwerty", corpus[42]} {
+		want := map[string]bool{}
+		for _, word := range corpus {
+			if editDistance(query, word) <= maxSuggestionDistance {
+				want[word] = true
+			}
+		}
+
+		got := map[string]bool{}
+		for _, s := range tree.search(query, maxSuggestionDistance) {
+			got[s.word] = true
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("searching for %q got %v want %v", query, got, want)
+		}
+	}
+}
+
+// the benchmark corpus is random letters, which is a bad case for a BK-tree as most words are a similar
+// distance from each other - real words with a real typo skip a lot more of the tree.
+func BenchmarkSuggest(b *testing.B) {
+	corpus := benchmarkCorpus()
+	dictionary := NewIndexedDictionary()
+	for _, word := range corpus {
+		dictionary.Add(word, "definition")
+	}
+
+	b.Run("bk-tree", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			dictionary.Suggest("abcdef")
+		}
+	})
+
+	// what we'd have to do without the tree
+	b.Run("scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, word := range corpus {
+				editDistance("abcdef", word)
+			}
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package dictionary
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// Maps are not safe to use from more than one goroutine at a time - if two write at once
+// Go stops the program with "fatal error: concurrent map writes".
+// Like the Counter in the sync chapter, we can protect the map with a lock.
+
+// SyncDictionary has the same methods and errors as Dictionary, but can be shared between goroutines.
+// A `sync.RWMutex` allows any number of readers at once, but a writer has the map to itself -
+// dictionaries are mostly read, so this is better than a plain Mutex.
+type SyncDictionary struct {
+	mu    sync.RWMutex
+	words Dictionary
+}
+
+// it contains a mutex, which must not be copied, so hand out a pointer
+func NewSyncDictionary() *SyncDictionary {
+	return &SyncDictionary{words: Dictionary{}}
+}
+
+func (d *SyncDictionary) Search(word string) (string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.words.Search(word)
+}
+
+// Add and Update need the write lock for the whole method, otherwise another goroutine
+// could add the word between us checking for it and writing it.
+func (d *SyncDictionary) Add(word, definition string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.words.Add(word, definition)
+}
+
+func (d *SyncDictionary) Update(word, definition string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.words.Update(word, definition)
+}
+
+func (d *SyncDictionary) Delete(word string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.words.Delete(word)
+}
+
+// With lots of goroutines writing at once they all queue up for the one lock.
+// ShardedDictionary splits the words across several SyncDictionaries ("shards") by hashing the word,
+// so goroutines working on different words usually take different locks.
+type ShardedDictionary struct {
+	shards []*SyncDictionary
+}
+
+func NewShardedDictionary(shardCount int) *ShardedDictionary {
+	if shardCount < 1 {
+		shardCount = 1
+	}
+
+	shards := make([]*SyncDictionary, shardCount)
+	for i := range shards {
+		shards[i] = NewSyncDictionary()
+	}
+	return &ShardedDictionary{shards: shards}
+}
+
+// the same word always hashes to the same shard
+func (d *ShardedDictionary) shard(word string) *SyncDictionary {
+	hash := fnv.New32a()
+	hash.Write([]byte(word))
+	return d.shards[hash.Sum32()%uint32(len(d.shards))]
+}
+
+func (d *ShardedDictionary) Search(word string) (string, error) {
+	return d.shard(word).Search(word)
+}
+
+func (d *ShardedDictionary) Add(word, definition string) error {
+	return d.shard(word).Add(word, definition)
+}
+
+func (d *ShardedDictionary) Update(word, definition string) error {
+	return d.shard(word).Update(word, definition)
+}
+
+func (d *ShardedDictionary) Delete(word string) error {
+	return d.shard(word).Delete(word)
+}
@@ -0,0 +1,132 @@
+package dictionary
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+var concurrentDictionaries = map[string]func() DictionaryStore{
+	"sync":    func() DictionaryStore { return NewSyncDictionary() },
+	"sharded": func() DictionaryStore { return NewShardedDictionary(8) },
+}
+
+func TestConcurrentDictionaries(t *testing.T) {
+	for name, newDictionary := range concurrentDictionaries {
+		t.Run(name, func(t *testing.T) {
+			t.Run("same errors as Dictionary", func(t *testing.T) {
+				dictionary := newDictionary()
+
+				assertError(t, dictionary.Add("test", "this is just a test"), nil)
+				assertError(t, dictionary.Add("test", "new test"), ErrWordExists)
+				assertError(t, dictionary.Update("unknown", "definition"), ErrWordDoesNotExist)
+				assertError(t, dictionary.Update("test", "new definition"), nil)
+
+				got, err := dictionary.Search("test")
+				assertError(t, err, nil)
+				assertStrings(t, got, "new definition")
+
+				dictionary.Delete("test")
+				_, err = dictionary.Search("test")
+				assertError(t, err, ErrNotFound)
+			})
+
+			t.Run("runs safely concurrently", func(t *testing.T) {
+				dictionary := newDictionary()
+				wantedCount := 1000
+
+				var wg sync.WaitGroup
+				wg.Add(wantedCount)
+				for i := 0; i < wantedCount; i++ {
+					go func(i int) {
+						defer wg.Done()
+						word := fmt.Sprintf("word%d", i)
+						dictionary.Add(word, "definition")
+						dictionary.Search(word)
+						dictionary.Update(word, "updated")
+					}(i)
+				}
+				wg.Wait()
+
+				for i := 0; i < wantedCount; i++ {
+					got, err := dictionary.Search(fmt.Sprintf("word%d", i))
+					assertError(t, err, nil)
+					assertStrings(t, got, "updated")
+				}
+			})
+		})
+	}
+}
+
+// compare against `sync.Map` from the standard library, using LoadOrStore to get the same "add if missing" behaviour.
+// run with `go test -bench=Concurrent`
+type syncMapDictionary struct {
+	words sync.Map
+}
+
+func (d *syncMapDictionary) Search(word string) (string, error) {
+	definition, ok := d.words.Load(word)
+	if !ok {
+		return "", ErrNotFound
+	}
+	return definition.(string), nil
+}
+
+func (d *syncMapDictionary) Add(word, definition string) error {
+	if _, loaded := d.words.LoadOrStore(word, definition); loaded {
+		return ErrWordExists
+	}
+	return nil
+}
+
+func (d *syncMapDictionary) Update(word, definition string) error {
+	if _, ok := d.words.Load(word); !ok {
+		return ErrWordDoesNotExist
+	}
+	d.words.Store(word, definition)
+	return nil
+}
+
+func (d *syncMapDictionary) Delete(word string) error {
+	d.words.Delete(word)
+	return nil
+}
+
+func BenchmarkConcurrentDictionaries(b *testing.B) {
+	dictionaries := map[string]func() DictionaryStore{
+		"sync":     concurrentDictionaries["sync"],
+		"sharded":  concurrentDictionaries["sharded"],
+		"sync.Map": func() DictionaryStore { return &syncMapDictionary{} },
+	}
+
+	words := make([]string, 1024)
+	for i := range words {
+		words[i] = fmt.Sprintf("word%d", i)
+	}
+
+	// mostly reads, with one write in every `writeEvery` operations
+	for _, writeEvery := range []int{2, 10, 100} {
+		for name, newDictionary := range dictionaries {
+			b.Run(fmt.Sprintf("%s/1 write in %d", name, writeEvery), func(b *testing.B) {
+				dictionary := newDictionary()
+				for _, word := range words {
+					dictionary.Add(word, "definition")
+				}
+
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					i := 0
+					for pb.Next() {
+						word := words[i%len(words)]
+						if i%writeEvery == 0 {
+							dictionary.Update(word, "new definition")
+						} else {
+							dictionary.Search(word)
+						}
+						i++
+					}
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"di"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// how long requests that have already started get to finish when we're asked to stop
+const shutdownTimeout = 5 * time.Second
+
+func main() {
+	server := &http.Server{Addr: ":5001", Handler: di.NewGreetServer()}
+
+	// ctx is cancelled when we get SIGINT (Ctrl+C)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	go func() {
+		// ListenAndServe always returns an error, ErrServerClosed just means Shutdown was called
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+	log.Println("listening on", server.Addr)
+
+	<-ctx.Done()
+	// stop listening for the signal, so pressing Ctrl+C again quits straight away
+	stop()
+	log.Println("shutting down")
+
+	// Shutdown stops accepting new connections and waits for the open requests to finish
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// you can run this using `go run ./cmd` from the 08-dependency-injection folder,
+// then visit http://localhost:5001/greet/Adam
@@ -1,9 +1,8 @@
-package main
+package di
 
 import (
 	"fmt"
 	"io"
-	"os"
 )
 
 // We want to write a function that greets someone, like in the hello-world section,
@@ -34,9 +33,10 @@ import (
 
 // if we try to call Greet to print to Stdout we get an error:
 // cannot use os.Stdout (variable of type *os.File) as *bytes.Buffer value in argument to Greet,
-func main() {
-	Greet(os.Stdout, "Adam")
-}
+// func main() {
+// 	Greet(os.Stdout, "Adam")
+// }
+// (main has moved to cmd/main.go, which serves greetings over http - so this is now a package other code can import)
 
 // Therefore we should change the Greet function to accept `io.Writer` instead of `bytes.Buffer`
 // as both `os.Stdout` and `bytes,Buffer` implement it.
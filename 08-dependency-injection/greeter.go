@@ -0,0 +1,104 @@
+package di
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// Greeter takes dependency injection further: instead of hard coding "Hello, %s" and writing it
+// as plain text, everything that might change is passed in -
+//   - the greeting, as a `text/template` like "Good morning, {{.Name}}!"
+//   - an Encoder that decides what the output looks like (plain text, JSON lines or HTML)
+//   - the io.Writer, just like Greet
+//
+// So the same Greeter code can write to a terminal, a log pipeline or a web page.
+
+// Greeting is what is passed to the Encoder - the name on its own as well as the whole greeting,
+// as a log pipeline might want to search by name
+type Greeting struct {
+	Name string `json:"name"`
+	Text string `json:"greeting"`
+}
+
+// Encoder writes one greeting to w, followed by a newline so each greeting is on its own line
+type Encoder interface {
+	Encode(w io.Writer, greeting Greeting) error
+}
+
+// PlainTextEncoder writes the greeting as it is, for a terminal
+type PlainTextEncoder struct{}
+
+func (PlainTextEncoder) Encode(w io.Writer, greeting Greeting) error {
+	_, err := fmt.Fprintln(w, greeting.Text)
+	return err
+}
+
+// JSONLinesEncoder writes each greeting as a JSON object on its own line, which log tools can read one line at a time
+type JSONLinesEncoder struct{}
+
+func (JSONLinesEncoder) Encode(w io.Writer, greeting Greeting) error {
+	encoder := json.NewEncoder(w)
+	// by default < > and & are escaped in case the JSON ends up in a web page, but these are log lines
+	encoder.SetEscapeHTML(false)
+	// Encode adds the newline for us
+	return encoder.Encode(greeting)
+}
+
+// HTMLEncoder escapes the greeting so it is safe to put in a web page.
+// The name usually comes from a user, and a "name" like <script>...</script> would otherwise be run by the browser.
+type HTMLEncoder struct{}
+
+func (HTMLEncoder) Encode(w io.Writer, greeting Greeting) error {
+	_, err := fmt.Fprintln(w, template.HTMLEscapeString(greeting.Text))
+	return err
+}
+
+// DefaultGreeting is the same greeting Greet writes
+const DefaultGreeting = "Hello, {{.Name}}"
+
+type Greeter struct {
+	template *template.Template
+	encoder  Encoder
+	writer   io.Writer
+}
+
+// NewGreeter returns an error if greeting isn't a valid template, e.g. "Hello, {{.Nmae}}",
+// so a typo is found when the Greeter is made rather than the first time someone is greeted.
+func NewGreeter(greeting string, encoder Encoder, writer io.Writer) (*Greeter, error) {
+	tmpl, err := template.New("greeting").Parse(greeting)
+	if err != nil {
+		return nil, err
+	}
+
+	// a template only finds out a field doesn't exist when it's run, so try it once
+	if err := tmpl.Execute(io.Discard, templateData{}); err != nil {
+		return nil, err
+	}
+
+	return &Greeter{template: tmpl, encoder: encoder, writer: writer}, nil
+}
+
+// templateData is what the greeting template can use, e.g. {{.Name}}
+type templateData struct {
+	Name string
+}
+
+func (g *Greeter) Greet(name string) error {
+	// render into a buffer first, so if the template fails half way nothing is written
+	var text strings.Builder
+	if err := g.template.Execute(&text, templateData{name}); err != nil {
+		return err
+	}
+
+	// likewise encode into a buffer, so the greeting reaches the writer in one Write rather than in pieces
+	var out bytes.Buffer
+	if err := g.encoder.Encode(&out, Greeting{Name: name, Text: text.String()}); err != nil {
+		return err
+	}
+	_, err := g.writer.Write(out.Bytes())
+	return err
+}
@@ -0,0 +1,106 @@
+package di
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestGreeter(t *testing.T) {
+	t.Run("default greeting", func(t *testing.T) {
+		buffer := bytes.Buffer{}
+		greeter := newTestGreeter(t, DefaultGreeting, PlainTextEncoder{}, &buffer)
+
+		assertNoError(t, greeter.Greet("Adam"))
+
+		assertOutput(t, buffer.String(), "Hello, Adam\n")
+	})
+
+	t.Run("custom greeting", func(t *testing.T) {
+		buffer := bytes.Buffer{}
+		greeter := newTestGreeter(t, "Good morning, {{.Name}}!", PlainTextEncoder{}, &buffer)
+
+		assertNoError(t, greeter.Greet("Adam"))
+		assertNoError(t, greeter.Greet("Chris"))
+
+		assertOutput(t, buffer.String(), "Good morning, Adam!\nGood morning, Chris!\n")
+	})
+
+	t.Run("encoders", func(t *testing.T) {
+		name := `<script>alert("hi")</script>`
+
+		cases := []struct {
+			name    string
+			encoder Encoder
+			want    string
+		}{
+			{"plain text", PlainTextEncoder{}, `Hello, <script>alert("hi")</script>` + "\n"},
+			{"json lines", JSONLinesEncoder{}, `{"name":"<script>alert(\"hi\")</script>","greeting":"Hello, <script>alert(\"hi\")</script>"}` + "\n"},
+			{"html", HTMLEncoder{}, "Hello, &lt;script&gt;alert(&#34;hi&#34;)&lt;/script&gt;\n"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				buffer := bytes.Buffer{}
+				greeter := newTestGreeter(t, DefaultGreeting, c.encoder, &buffer)
+
+				assertNoError(t, greeter.Greet(name))
+
+				assertOutput(t, buffer.String(), c.want)
+			})
+		}
+	})
+
+	t.Run("invalid templates", func(t *testing.T) {
+		for _, greeting := range []string{"Hello, {{.Name", "Hello, {{.Nmae}}"} {
+			_, err := NewGreeter(greeting, PlainTextEncoder{}, &bytes.Buffer{})
+
+			if err == nil {
+				t.Errorf("expected an error for %q", greeting)
+			}
+		}
+	})
+
+	t.Run("writer errors are returned", func(t *testing.T) {
+		greeter := newTestGreeter(t, DefaultGreeting, PlainTextEncoder{}, FailingWriter{})
+
+		err := greeter.Greet("Adam")
+
+		if !errors.Is(err, errWriteFailed) {
+			t.Errorf("got %v want %v", err, errWriteFailed)
+		}
+	})
+}
+
+var errWriteFailed = errors.New("write failed")
+
+// FailingWriter is an io.Writer that can't be written to, like a closed file
+type FailingWriter struct{}
+
+func (FailingWriter) Write([]byte) (int, error) {
+	return 0, errWriteFailed
+}
+
+func newTestGreeter(t testing.TB, greeting string, encoder Encoder, writer io.Writer) *Greeter {
+	t.Helper()
+	greeter, err := NewGreeter(greeting, encoder, writer)
+	if err != nil {
+		t.Fatal("could not make greeter:", err)
+	}
+	return greeter
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal("didn't expect an error but got one:", err)
+	}
+}
+
+func assertOutput(t testing.TB, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package di
+
+import (
+	"net/http"
+	"text/template"
+)
+
+// `http.ResponseWriter` implements io.Writer, so Greet can write straight into an http response
+// without knowing anything about http.
+//
+// The name comes from whoever sends the request, so it must be escaped: a "name" like
+// <script>...</script> would otherwise be run by the browser of anyone who opens the link (called "cross-site scripting").
+
+// defaultName is who we greet when the request doesn't say
+const defaultName = "World"
+
+// NewGreetServer routes /greet/{name} and /greet?name={name} to GreetHandler
+func NewGreetServer() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /greet/{name}", GreetHandler)
+	mux.HandleFunc("GET /greet", GreetHandler)
+	return mux
+}
+
+func GreetHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if name == "" {
+		name = r.URL.Query().Get("name")
+	}
+	if name == "" {
+		name = defaultName
+	}
+
+	// tell the browser what we're sending, and not to guess something else from the content
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	Greet(w, template.HTMLEscapeString(name))
+}
@@ -0,0 +1,47 @@
+package di
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGreetHandler(t *testing.T) {
+	cases := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"name in the path", "/greet/Adam", "Hello, Adam"},
+		{"name in the query", "/greet?name=Chris", "Hello, Chris"},
+		{"no name", "/greet", "Hello, World"},
+		{"escaped path", "/greet/Adam%20Smith", "Hello, Adam Smith"},
+		{"html is escaped", "/greet?name=%3Cscript%3Ealert(%22hi%22)%3C/script%3E", "Hello, &lt;script&gt;alert(&#34;hi&#34;)&lt;/script&gt;"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, c.path, nil)
+			response := httptest.NewRecorder()
+
+			NewGreetServer().ServeHTTP(response, request)
+
+			if response.Code != http.StatusOK {
+				t.Errorf("got status %d want %d", response.Code, http.StatusOK)
+			}
+			assertOutput(t, response.Body.String(), c.want)
+			assertOutput(t, response.Header().Get("Content-Type"), "text/html; charset=utf-8")
+		})
+	}
+
+	t.Run("only GET", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/greet/Adam", nil)
+		response := httptest.NewRecorder()
+
+		NewGreetServer().ServeHTTP(response, request)
+
+		if response.Code != http.StatusMethodNotAllowed {
+			t.Errorf("got status %d want %d", response.Code, http.StatusMethodNotAllowed)
+		}
+	})
+}
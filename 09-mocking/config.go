@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/template"
+)
+
+// Configurable countdown
+// Countdown always goes 3, 2, 1, Go!. CountdownConfig lets the caller choose where to start and stop,
+// how much to count down by, what to say at the end, and how each number is written.
+// Countdown is now just the countdown with DefaultCountdownConfig, so it behaves exactly as before.
+
+type CountdownConfig struct {
+	Start int
+	// End is the last number written
+	End int
+	// Step is how much to count down by, it must be more than zero
+	Step         int
+	FinalMessage string
+	// Format is a text/template for each number, given a Tick, e.g. "T-minus {{.Number}}\n"
+	Format string
+}
+
+// Tick is what the Format template can use
+type Tick struct {
+	Number int
+}
+
+var DefaultCountdownConfig = CountdownConfig{
+	Start:        countdownStart,
+	End:          1,
+	Step:         1,
+	FinalMessage: finalWord,
+	Format:       "{{.Number}}\n",
+}
+
+var (
+	ErrInvalidStep   = errors.New("countdown step must be more than zero")
+	ErrStartBelowEnd = errors.New("countdown start must not be below the end")
+	ErrInvalidFormat = errors.New("countdown format is not a valid template")
+)
+
+// Validate checks the config makes sense - with a step of 0, or a start below the end,
+// the countdown would never finish or never start
+func (c CountdownConfig) Validate() error {
+	_, err := c.parse()
+	return err
+}
+
+func (c CountdownConfig) parse() (*template.Template, error) {
+	if c.Step <= 0 {
+		return nil, ErrInvalidStep
+	}
+	if c.Start < c.End {
+		return nil, ErrStartBelowEnd
+	}
+
+	tmpl, err := template.New("tick").Parse(c.Format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	// a template only finds out a field doesn't exist when it's run, so try it once
+	if err := tmpl.Execute(io.Discard, Tick{}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	return tmpl, nil
+}
+
+// CountdownWithConfig writes each number from config.Start down to config.End with a sleep after each,
+// then the final message. Nothing is written if the config isn't valid.
+func CountdownWithConfig(out io.Writer, sleeper Sleeper, config CountdownConfig) error {
+	return countdown(context.Background(), out, sleeper, config)
+}
+
+// countdown is CountdownWithConfig, stopping if ctx is cancelled (see context.go)
+func countdown(ctx context.Context, out io.Writer, sleeper Sleeper, config CountdownConfig) error {
+	tmpl, err := config.parse()
+	if err != nil {
+		return err
+	}
+
+	for i := config.Start; i >= config.End; i -= config.Step {
+		if ctx.Err() != nil {
+			return abort(ctx, out)
+		}
+
+		// render into a buffer so each number is one Write, however many pieces the template has
+		var tick bytes.Buffer
+		if err := tmpl.Execute(&tick, Tick{i}); err != nil {
+			return err
+		}
+		if _, err := out.Write(tick.Bytes()); err != nil {
+			return err
+		}
+		if err := sleepContext(ctx, sleeper); err != nil {
+			return abort(ctx, out)
+		}
+	}
+
+	_, err = fmt.Fprint(out, config.FinalMessage)
+	return err
+}
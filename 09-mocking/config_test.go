@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"learn-go-with-tests/spy"
+)
+
+func TestCountdownWithConfig(t *testing.T) {
+	t.Run("default config is the same as Countdown", func(t *testing.T) {
+		withConfig := &bytes.Buffer{}
+		plain := &bytes.Buffer{}
+
+		err := CountdownWithConfig(withConfig, &SpySleeper{}, DefaultCountdownConfig)
+		Countdown(plain, &SpySleeper{})
+
+		assertNoError(t, err)
+		assertCountdown(t, withConfig.String(), plain.String())
+	})
+
+	t.Run("start, end, step and final message", func(t *testing.T) {
+		recorder := spy.NewRecorder(nil)
+		config := CountdownConfig{Start: 10, End: 2, Step: 3, FinalMessage: "Lift off!", Format: "{{.Number}}\n"}
+
+		err := CountdownWithConfig(recorder.Writer(write, nil), recorder.Sleeper(sleep, nil), config)
+
+		assertNoError(t, err)
+		spy.AssertInterleaved(t, recorder, write, sleep)
+		spy.AssertCount(t, recorder, sleep, 3)
+		spy.AssertCalledWith(t, recorder, write, "4\n")
+		spy.AssertCalledWith(t, recorder, write, "Lift off!")
+	})
+
+	t.Run("counting down to zero", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		config := CountdownConfig{Start: 2, End: 0, Step: 1, FinalMessage: "Go!", Format: "{{.Number}} "}
+
+		assertNoError(t, CountdownWithConfig(buffer, &SpySleeper{}, config))
+
+		assertCountdown(t, buffer.String(), "2 1 0 Go!")
+	})
+
+	t.Run("format with more than one part is still one write per number", func(t *testing.T) {
+		recorder := spy.NewRecorder(nil)
+		config := CountdownConfig{Start: 2, End: 1, Step: 1, FinalMessage: "Go!", Format: "T-minus {{.Number}}\n"}
+
+		assertNoError(t, CountdownWithConfig(recorder.Writer(write, nil), recorder.Sleeper(sleep, nil), config))
+
+		spy.AssertCalls(t, recorder, write, sleep, write, sleep, write)
+		spy.AssertCalledWith(t, recorder, write, "T-minus 2\n")
+	})
+
+	t.Run("invalid configs", func(t *testing.T) {
+		cases := []struct {
+			name   string
+			config CountdownConfig
+			want   error
+		}{
+			{"zero step", CountdownConfig{Start: 3, End: 1, Step: 0}, ErrInvalidStep},
+			{"negative step", CountdownConfig{Start: 3, End: 1, Step: -1}, ErrInvalidStep},
+			{"start below end", CountdownConfig{Start: 1, End: 3, Step: 1}, ErrStartBelowEnd},
+			{"unparsable format", CountdownConfig{Start: 3, End: 1, Step: 1, Format: "{{.Number"}, ErrInvalidFormat},
+			{"unknown field", CountdownConfig{Start: 3, End: 1, Step: 1, Format: "{{.Count}}"}, ErrInvalidFormat},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				buffer := &bytes.Buffer{}
+
+				err := CountdownWithConfig(buffer, &SpySleeper{}, c.config)
+
+				if !errors.Is(err, c.want) {
+					t.Errorf("got error %v want %v", err, c.want)
+				}
+				if !errors.Is(c.config.Validate(), c.want) {
+					t.Errorf("Validate got %v want %v", c.config.Validate(), c.want)
+				}
+				if buffer.Len() != 0 {
+					t.Errorf("should not write anything for an invalid config, wrote %q", buffer.String())
+				}
+			})
+		}
+	})
+}
+
+func assertCountdown(t testing.TB, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal("didn't expect an error but got one:", err)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"learn-go-with-tests/clock"
+)
+
+// Cancelling a countdown
+// Once Countdown has started it can't be stopped, and `time.Sleep` can't be woken up early either.
+// CountdownContext takes a context like the Store in the context chapter: when the context is cancelled
+// (e.g. the user pressed Ctrl+C) the countdown stops straight away, writes abortedMessage and returns ctx.Err().
+
+const abortedMessage = "Aborted"
+
+// ContextSleeper is a Sleeper that can be woken up early by cancelling ctx.
+// It returns ctx.Err() if it was woken up, or nil if it slept for the whole time.
+type ContextSleeper interface {
+	SleepContext(ctx context.Context) error
+}
+
+// CancellableSleeper sleeps for duration, unless the context is cancelled first
+type CancellableSleeper struct {
+	duration time.Duration
+	// clock is clock.Real in main - like ConfigurableSleeper's sleep, it's a field so the tests can swap it for a fake
+	clock clock.Clock
+}
+
+func (c *CancellableSleeper) Sleep() {
+	c.clock.Sleep(c.duration)
+}
+
+func (c *CancellableSleeper) SleepContext(ctx context.Context) error {
+	// whichever happens first: the time is up, or the context is cancelled
+	select {
+	case <-c.clock.After(c.duration):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CountdownContext is Countdown, but stops when ctx is cancelled.
+// If sleeper is also a ContextSleeper the sleep is interrupted too, otherwise the countdown stops
+// when the current sleep finishes.
+func CountdownContext(ctx context.Context, out io.Writer, sleeper Sleeper) error {
+	return countdown(ctx, out, sleeper, DefaultCountdownConfig)
+}
+
+// sleepContext sleeps with the sleeper, waking early if it's a ContextSleeper and ctx is cancelled
+func sleepContext(ctx context.Context, sleeper Sleeper) error {
+	// a "type assertion" checks if the value in an interface also has another interface's methods
+	if s, ok := sleeper.(ContextSleeper); ok {
+		return s.SleepContext(ctx)
+	}
+	sleeper.Sleep()
+	return ctx.Err()
+}
+
+func abort(ctx context.Context, out io.Writer) error {
+	fmt.Fprint(out, abortedMessage)
+	return ctx.Err()
+}
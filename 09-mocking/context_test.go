@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"learn-go-with-tests/clock"
+)
+
+func TestCountdownContext(t *testing.T) {
+	t.Run("counts down like Countdown if not cancelled", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+
+		err := CountdownContext(context.Background(), buffer, &SpySleeper{})
+
+		assertNoError(t, err)
+		assertCountdown(t, buffer.String(), "3\n2\n1\nGo!")
+	})
+
+	t.Run("already cancelled", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		spySleeper := &SpySleeper{}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := CountdownContext(ctx, buffer, spySleeper)
+
+		assertContextError(t, err, context.Canceled)
+		assertCountdown(t, buffer.String(), "Aborted")
+		if spySleeper.Calls != 0 {
+			t.Errorf("should not have slept, slept %d times", spySleeper.Calls)
+		}
+	})
+
+	t.Run("interrupts a ContextSleeper", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		ctx, cancel := context.WithCancel(context.Background())
+		sleeper := &SpyCancellingSleeper{cancel: cancel, cancelOn: 2}
+
+		err := CountdownContext(ctx, buffer, sleeper)
+
+		assertContextError(t, err, context.Canceled)
+		assertCountdown(t, buffer.String(), "3\n2\nAborted")
+	})
+
+	t.Run("a plain Sleeper stops after its sleep", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		ctx, cancel := context.WithCancel(context.Background())
+		sleeper := &SpyCancellingSleeper{cancel: cancel, cancelOn: 1}
+
+		// hide SleepContext, so it is only a Sleeper
+		err := CountdownContext(ctx, buffer, struct{ Sleeper }{sleeper})
+
+		assertContextError(t, err, context.Canceled)
+		assertCountdown(t, buffer.String(), "3\nAborted")
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+		defer cancel()
+
+		err := CountdownContext(ctx, buffer, &SpySleeper{})
+
+		assertContextError(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestCancellableSleeper(t *testing.T) {
+	t.Run("sleeps for the duration", func(t *testing.T) {
+		fakeClock := clock.NewFakeClock(testStart)
+		sleeper := &CancellableSleeper{5 * time.Second, fakeClock}
+		done := sleepInBackground(context.Background(), sleeper)
+
+		fakeClock.BlockUntil(1)
+		fakeClock.Advance(4 * time.Second)
+		assertStillSleeping(t, done)
+
+		fakeClock.Advance(time.Second)
+		assertNoError(t, <-done)
+	})
+
+	t.Run("wakes up when cancelled", func(t *testing.T) {
+		// the fake clock is never advanced, like a very long sleep
+		sleeper := &CancellableSleeper{time.Hour, clock.NewFakeClock(testStart)}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		go cancel()
+		err := sleeper.SleepContext(ctx)
+
+		assertContextError(t, err, context.Canceled)
+	})
+
+	t.Run("a whole countdown without waiting", func(t *testing.T) {
+		fakeClock := clock.NewFakeClock(testStart)
+		buffer := &SyncBuffer{}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		done := make(chan error)
+		go func() {
+			done <- CountdownContext(ctx, buffer, &CancellableSleeper{time.Second, fakeClock})
+		}()
+
+		// two seconds in, we should be part way through the last sleep
+		for range 2 {
+			fakeClock.BlockUntil(1)
+			fakeClock.Advance(time.Second)
+		}
+		fakeClock.BlockUntil(1)
+		assertCountdown(t, buffer.String(), "3\n2\n1\n")
+
+		cancel()
+		assertContextError(t, <-done, context.Canceled)
+		assertCountdown(t, buffer.String(), "3\n2\n1\nAborted")
+	})
+}
+
+var testStart = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func sleepInBackground(ctx context.Context, sleeper ContextSleeper) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- sleeper.SleepContext(ctx)
+	}()
+	return done
+}
+
+func assertStillSleeping(t testing.TB, done <-chan error) {
+	t.Helper()
+	select {
+	case err := <-done:
+		t.Fatalf("woke up too early, with error %v", err)
+	default:
+	}
+}
+
+// SyncBuffer is a bytes.Buffer that can be written by the countdown's goroutine while the test reads it
+type SyncBuffer struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *SyncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *SyncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.String()
+}
+
+// SpyCancellingSleeper cancels the context on the cancelOn'th sleep, like someone pressing Ctrl+C part way through
+type SpyCancellingSleeper struct {
+	cancel   context.CancelFunc
+	cancelOn int
+	calls    int
+}
+
+func (s *SpyCancellingSleeper) Sleep() {
+	s.calls++
+	if s.calls == s.cancelOn {
+		s.cancel()
+	}
+}
+
+func (s *SpyCancellingSleeper) SleepContext(ctx context.Context) error {
+	s.Sleep()
+	return ctx.Err()
+}
+
+func assertContextError(t testing.TB, got, want error) {
+	t.Helper()
+	if !errors.Is(got, want) {
+		t.Errorf("got error %v want %v", got, want)
+	}
+}
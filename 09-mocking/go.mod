@@ -1,3 +1,14 @@
 module mocking
 
 go 1.23.2
+
+require (
+	learn-go-with-tests/clock v0.0.0
+	learn-go-with-tests/spy v0.0.0
+)
+
+// the clock and spy packages live in this repo, rather than being downloaded
+replace (
+	learn-go-with-tests/clock => ../clock
+	learn-go-with-tests/spy => ../spy
+)
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"time"
+
+	"learn-go-with-tests/clock"
 )
 
 // write a function that will count down a 1 second pause in between:
@@ -82,13 +86,19 @@ func (s *SpySleeper) Sleep() {
 // how many times it's been called etc. In this case we're keeping track of many times Sleep() is called
 
 // Now write the rewrite the function to accept Sleeper
+// func Countdown(out io.Writer, sleeper Sleeper) {
+// 	for i := countdownStart; i > 0; i-- {
+// 		fmt.Fprintln(out, i)
+// 		// we call sleeper instead of directly `time.Sleep(1 * time.Second)`
+// 		sleeper.Sleep()
+// 	}
+// 	fmt.Fprint(out, finalWord)
+// }
+
+// Countdown is now the configurable countdown (see config.go) with the default config, which counts 3, 2, 1, Go!
+// The default config is always valid, so there's no error to return.
 func Countdown(out io.Writer, sleeper Sleeper) {
-	for i := countdownStart; i > 0; i-- {
-		fmt.Fprintln(out, i)
-		// we call sleeper instead of directly `time.Sleep(1 * time.Second)`
-		sleeper.Sleep()
-	}
-	fmt.Fprint(out, finalWord)
+	CountdownWithConfig(out, sleeper, DefaultCountdownConfig)
 }
 
 // create a "real" sleeper which implements the interface we need
@@ -153,9 +163,22 @@ func (c *ConfigurableSleeper) Sleep() {
 // Now we can use the configurable sleeping the main() function
 // as it's now using configurable sleeper, the defaultSleeper is no longer needed and can be deleted.
 // this now means that we have a more generic sleeper with arbitrarily long countdowns
+// func main() {
+// 	sleeper := &ConfigurableSleeper{1 * time.Second, time.Sleep}
+// 	Countdown(os.Stdout, sleeper)
+// }
+
+// and now with CountdownContext (see context.go) pressing Ctrl+C stops the countdown part way through a sleep
 func main() {
-	sleeper := &ConfigurableSleeper{1 * time.Second, time.Sleep}
-	Countdown(os.Stdout, sleeper)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	sleeper := &CancellableSleeper{1 * time.Second, clock.Real{}}
+	// in a terminal the countdown stays on one line, see render.go
+	if err := CountdownContext(ctx, NewRenderer(os.Stdout), sleeper); err != nil {
+		fmt.Println()
+		os.Exit(1)
+	}
 }
 
 // Use spies with caution as it lets you see inside the algorythm you are writing, which can be useful
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// Redrawing one line
+// In a terminal it looks better if the countdown stays on one line, with each number replacing the last.
+// Terminals understand "ANSI escape sequences" - special characters that move the cursor or clear the
+// screen instead of being printed:
+//   - "\r" (carriage return) moves the cursor back to the start of the line
+//   - "\x1b[2K" clears the whole line
+//
+// The renderer is an io.Writer, so Countdown doesn't change at all - each number is one Write (see config.go),
+// so each Write just becomes "go back, clear the line, write the number".
+//
+// If the output isn't a terminal (a file, a pipe, or a bytes.Buffer in the tests) the escape sequences would
+// just be junk in the output, so NewRenderer gives back the writer it was given and the output is the same as before.
+
+const (
+	carriageReturn = "\r"
+	clearLine      = "\x1b[2K"
+)
+
+// NewRenderer redraws the countdown in place if out is a terminal, otherwise it returns out unchanged
+func NewRenderer(out io.Writer) io.Writer {
+	return newRenderer(out, isTerminal)
+}
+
+// the check for a terminal is passed in, as the tests aren't run in one
+func newRenderer(out io.Writer, isTerminal func(io.Writer) bool) io.Writer {
+	if !isTerminal(out) {
+		return out
+	}
+	return &InPlaceRenderer{out}
+}
+
+// isTerminal reports whether out is a terminal that understands escape sequences.
+// Terminals are "character devices", while files and pipes aren't. A TERM of "dumb" means the terminal
+// can't move the cursor (e.g. the output window of some editors).
+func isTerminal(out io.Writer) bool {
+	file, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0 && os.Getenv("TERM") != "dumb"
+}
+
+type InPlaceRenderer struct {
+	out io.Writer
+}
+
+// Write replaces the current line with p. The newline at the end of p is left off,
+// as it would move the cursor to the next line.
+func (r *InPlaceRenderer) Write(p []byte) (int, error) {
+	text := strings.TrimRight(string(p), "\n")
+	if _, err := io.WriteString(r.out, carriageReturn+clearLine+text); err != nil {
+		return 0, err
+	}
+	// io.Writer must say how much of p was written - all of it, even though we wrote something different
+	return len(p), nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderer(t *testing.T) {
+	terminal := func(io.Writer) bool { return true }
+	notTerminal := func(io.Writer) bool { return false }
+
+	t.Run("redraws each number on the same line in a terminal", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+
+		Countdown(newRenderer(buffer, terminal), &SpySleeper{})
+
+		want := "\r\x1b[2K3" + "\r\x1b[2K2" + "\r\x1b[2K1" + "\r\x1b[2KGo!"
+		assertCountdown(t, buffer.String(), want)
+	})
+
+	t.Run("aborted replaces the number too", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		ctx, cancel := context.WithCancel(context.Background())
+		sleeper := &SpyCancellingSleeper{cancel: cancel, cancelOn: 1}
+
+		CountdownContext(ctx, newRenderer(buffer, terminal), sleeper)
+
+		assertCountdown(t, buffer.String(), "\r\x1b[2K3\r\x1b[2KAborted")
+	})
+
+	t.Run("same output as before if not a terminal", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+
+		renderer := newRenderer(buffer, notTerminal)
+		Countdown(renderer, &SpySleeper{})
+
+		if renderer != io.Writer(buffer) {
+			t.Errorf("expected the buffer itself back, got %T", renderer)
+		}
+		assertCountdown(t, buffer.String(), "3\n2\n1\nGo!")
+	})
+
+	t.Run("reports the length it was given", func(t *testing.T) {
+		n, err := (&InPlaceRenderer{&bytes.Buffer{}}).Write([]byte("3\n"))
+
+		assertNoError(t, err)
+		if n != 2 {
+			t.Errorf("got %d want 2", n)
+		}
+	})
+}
+
+func TestIsTerminal(t *testing.T) {
+	t.Run("a buffer is not a terminal", func(t *testing.T) {
+		if isTerminal(&bytes.Buffer{}) {
+			t.Error("bytes.Buffer should not be a terminal")
+		}
+	})
+
+	t.Run("a file is not a terminal", func(t *testing.T) {
+		file, err := os.Create(filepath.Join(t.TempDir(), "countdown.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+
+		if isTerminal(file) {
+			t.Error("a regular file should not be a terminal")
+		}
+	})
+}
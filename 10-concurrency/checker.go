@@ -0,0 +1,147 @@
+package concurrency
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"learn-go-with-tests/clock"
+)
+
+// Richer results
+// A WebsiteChecker only says true or false, so a site returning 500 errors, a typo in a domain name
+// and a site that takes a minute to answer all look the same. A DetailedChecker returns a CheckResult
+// with the status code, how long it took, the error if there was one, and a Health that sums it all up.
+
+type Health int
+
+const (
+	// Up means the site answered with a 2xx or 3xx status
+	Up Health = iota
+	// Slow means it answered with a good status, but took longer than the slow threshold
+	Slow
+	// Down means it answered, but with a 4xx or 5xx status
+	Down
+	// Unreachable means there was no answer at all - e.g. the domain doesn't exist, or it timed out
+	Unreachable
+	// Unfinished means the check was cancelled before it finished, or never started (see context.go)
+	Unfinished
+)
+
+// String makes a Health print as a word instead of a number, e.g. in test failures
+func (h Health) String() string {
+	switch h {
+	case Up:
+		return "up"
+	case Slow:
+		return "slow"
+	case Down:
+		return "down"
+	case Unreachable:
+		return "unreachable"
+	case Unfinished:
+		return "unfinished"
+	default:
+		return "unknown"
+	}
+}
+
+type CheckResult struct {
+	URL string
+	// StatusCode is 0 if there was no response
+	StatusCode int
+	Latency    time.Duration
+	Err        error
+	Health     Health
+}
+
+// DetailedChecker is the richer version of WebsiteChecker
+type DetailedChecker func(string) CheckResult
+
+// HTTPChecker checks a url by fetching it. Redirects are followed, and the status of the final page is used.
+type HTTPChecker struct {
+	client *http.Client
+	// responses that take longer than this are Slow, 0 means never
+	slowThreshold time.Duration
+	// the clock is used to time each request, so the tests can make a request "take" as long as they like
+	clock clock.Clock
+}
+
+// NewHTTPChecker uses http.DefaultClient if client is nil, and the real clock if clk is nil,
+// so NewHTTPChecker(nil, 0, nil) is a checker with sensible defaults
+func NewHTTPChecker(client *http.Client, slowThreshold time.Duration, clk clock.Clock) *HTTPChecker {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if clk == nil {
+		clk = clock.Real{}
+	}
+	return &HTTPChecker{client: client, slowThreshold: slowThreshold, clock: clk}
+}
+
+func (c *HTTPChecker) Check(url string) CheckResult {
+	return c.CheckContext(context.Background(), url)
+}
+
+// CheckContext gives up on the request when ctx is cancelled
+func (c *HTTPChecker) CheckContext(ctx context.Context, url string) CheckResult {
+	start := c.clock.Now()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return CheckResult{URL: url, Err: err, Health: Unreachable}
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return CheckResult{URL: url, Latency: c.clock.Now().Sub(start), Err: err, Health: Unreachable}
+	}
+
+	// the body has to be read and closed so the connection can be used again - but we don't need
+	// all of a big page to know the site is up, so only read a little of it
+	io.Copy(io.Discard, io.LimitReader(response.Body, 4096)) //nolint:errcheck
+	response.Body.Close()
+	latency := c.clock.Now().Sub(start)
+
+	return CheckResult{
+		URL:        url,
+		StatusCode: response.StatusCode,
+		Latency:    latency,
+		Health:     c.classify(response.StatusCode, latency),
+	}
+}
+
+func (c *HTTPChecker) classify(status int, latency time.Duration) Health {
+	switch {
+	case status >= 400:
+		return Down
+	case c.slowThreshold > 0 && latency > c.slowThreshold:
+		return Slow
+	default:
+		return Up
+	}
+}
+
+// FromWebsiteChecker lets an old WebsiteChecker be used where a DetailedChecker is needed.
+// A bool can only tell us Up or Down.
+func FromWebsiteChecker(wc WebsiteChecker) DetailedChecker {
+	return func(url string) CheckResult {
+		if wc(url) {
+			return CheckResult{URL: url, Health: Up}
+		}
+		return CheckResult{URL: url, Health: Down}
+	}
+}
+
+// AsWebsiteChecker lets a DetailedChecker be used with CheckWebsites. Slow sites still count as up.
+func AsWebsiteChecker(dc DetailedChecker) WebsiteChecker {
+	return func(url string) bool {
+		health := dc(url).Health
+		return health == Up || health == Slow
+	}
+}
+
+// CheckWebsitesDetailed is CheckWebsitesWithOptions for a DetailedChecker
+func CheckWebsitesDetailed(dc DetailedChecker, urls []string, options CheckOptions) map[string]CheckResult {
+	return checkAll(dc, urls, options)
+}
@@ -0,0 +1,163 @@
+package concurrency
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"learn-go-with-tests/clock"
+)
+
+var testStart = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func TestHTTPChecker(t *testing.T) {
+	t.Run("status codes", func(t *testing.T) {
+		cases := []struct {
+			status int
+			want   Health
+		}{
+			{http.StatusOK, Up},
+			{http.StatusNoContent, Up},
+			{http.StatusNotFound, Down},
+			{http.StatusInternalServerError, Down},
+		}
+
+		for _, c := range cases {
+			t.Run(http.StatusText(c.status), func(t *testing.T) {
+				server := makeStatusServer(c.status)
+				defer server.Close()
+				checker := NewHTTPChecker(server.Client(), 0, clock.Real{})
+
+				got := checker.Check(server.URL)
+
+				assertResult(t, got, CheckResult{URL: server.URL, StatusCode: c.status, Health: c.want})
+			})
+		}
+	})
+
+	t.Run("defaults for a nil client and clock", func(t *testing.T) {
+		server := makeStatusServer(http.StatusOK)
+		defer server.Close()
+		checker := NewHTTPChecker(nil, 0, nil)
+
+		got := checker.Check(server.URL)
+
+		assertResult(t, got, CheckResult{URL: server.URL, StatusCode: http.StatusOK, Health: Up})
+	})
+
+	t.Run("redirects are followed", func(t *testing.T) {
+		target := makeStatusServer(http.StatusOK)
+		defer target.Close()
+		redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+		defer redirect.Close()
+		checker := NewHTTPChecker(http.DefaultClient, 0, clock.Real{})
+
+		got := checker.Check(redirect.URL)
+
+		assertResult(t, got, CheckResult{URL: redirect.URL, StatusCode: http.StatusOK, Health: Up})
+	})
+
+	t.Run("latency and slow sites", func(t *testing.T) {
+		fakeClock := clock.NewFakeClock(testStart)
+		// the server moves the fake clock on, so the request "takes" two seconds without really waiting
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fakeClock.Advance(2 * time.Second)
+		}))
+		defer server.Close()
+		checker := NewHTTPChecker(server.Client(), time.Second, fakeClock)
+
+		got := checker.Check(server.URL)
+
+		assertResult(t, got, CheckResult{URL: server.URL, StatusCode: http.StatusOK, Latency: 2 * time.Second, Health: Slow})
+	})
+
+	t.Run("nothing listening", func(t *testing.T) {
+		server := makeStatusServer(http.StatusOK)
+		url := server.URL
+		server.Close()
+		checker := NewHTTPChecker(http.DefaultClient, 0, clock.Real{})
+
+		got := checker.Check(url)
+
+		if got.Health != Unreachable || got.Err == nil || got.StatusCode != 0 {
+			t.Errorf("got %+v, want it unreachable with an error", got)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		stalled := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-stalled
+		}))
+		defer server.Close()
+		defer close(stalled)
+		checker := NewHTTPChecker(&http.Client{Timeout: 10 * time.Millisecond}, 0, clock.Real{})
+
+		got := checker.Check(server.URL)
+
+		var netErr net.Error
+		if got.Health != Unreachable || !errors.As(got.Err, &netErr) || !netErr.Timeout() {
+			t.Errorf("got %+v, want it unreachable with a timeout error", got)
+		}
+	})
+}
+
+func TestCheckerAdapters(t *testing.T) {
+	t.Run("old checker as a detailed checker", func(t *testing.T) {
+		detailed := FromWebsiteChecker(mockWebsiteChecker)
+
+		assertResult(t, detailed("http://google.com"), CheckResult{URL: "http://google.com", Health: Up})
+		assertResult(t, detailed("waat://furhurterwe.geds"), CheckResult{URL: "waat://furhurterwe.geds", Health: Down})
+	})
+
+	t.Run("detailed checker with CheckWebsites", func(t *testing.T) {
+		healths := map[string]Health{"up": Up, "slow": Slow, "down": Down, "unreachable": Unreachable}
+		detailed := func(url string) CheckResult { return CheckResult{URL: url, Health: healths[url]} }
+
+		got := CheckWebsites(AsWebsiteChecker(detailed), []string{"up", "slow", "down", "unreachable"})
+
+		want := map[string]bool{"up": true, "slow": true, "down": false, "unreachable": false}
+		for url, ok := range want {
+			if got[url] != ok {
+				t.Errorf("%s: got %v want %v", url, got[url], ok)
+			}
+		}
+	})
+}
+
+func TestCheckWebsitesDetailed(t *testing.T) {
+	up := makeStatusServer(http.StatusOK)
+	defer up.Close()
+	down := makeStatusServer(http.StatusServiceUnavailable)
+	defer down.Close()
+	checker := NewHTTPChecker(http.DefaultClient, 0, clock.Real{})
+
+	got := CheckWebsitesDetailed(checker.Check, []string{up.URL, down.URL, up.URL}, CheckOptions{Concurrency: 2})
+
+	if len(got) != 2 {
+		t.Fatalf("got %d results want 2", len(got))
+	}
+	if got[up.URL].Health != Up || got[down.URL].Health != Down {
+		t.Errorf("got %v and %v, want up and down", got[up.URL].Health, got[down.URL].Health)
+	}
+}
+
+func makeStatusServer(status int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+}
+
+// assertResult ignores the latency when want doesn't have one, as it depends on how fast the machine is
+func assertResult(t testing.TB, got, want CheckResult) {
+	t.Helper()
+	if want.Latency == 0 {
+		got.Latency = 0
+	}
+	if got != want {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
@@ -0,0 +1,146 @@
+package concurrency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Stopping part way
+// CheckWebsites waits for every url, so one site that never answers makes it wait forever.
+// CheckWebsitesContext takes a context, like the Store in the context chapter:
+//   - when the context is cancelled, or its deadline passes, it stops and returns what it has so far
+//   - each check also has its own timeout (ContextOptions.Timeout), so one slow site can't use up the whole deadline
+//
+// Urls that didn't finish are still in the results, with the Health Unfinished, so it's clear which ones
+// we don't know about rather than them silently missing.
+
+// ContextOptions are the CheckOptions plus a timeout for each check. The timeout is only here, as
+// it needs a context to tell the check to stop.
+type ContextOptions struct {
+	// Concurrency is the most checks that run at the same time. 0 means no limit.
+	Concurrency int
+	// Timeout is how long each check gets, 0 means no limit.
+	Timeout time.Duration
+}
+
+// ContextChecker is a DetailedChecker that should give up when ctx is cancelled, like HTTPChecker.CheckContext
+type ContextChecker func(ctx context.Context, url string) CheckResult
+
+// WithContext lets a DetailedChecker be used as a ContextChecker. It can't be interrupted, but
+// CheckWebsitesContext still stops waiting for it when the context is cancelled.
+func WithContext(dc DetailedChecker) ContextChecker {
+	return func(_ context.Context, url string) CheckResult {
+		return dc(url)
+	}
+}
+
+// CheckWebsitesContext checks urls like CheckWebsitesDetailed, stopping when ctx is done.
+// The error is ctx.Err() if it stopped before every url was checked, and those urls are Unfinished.
+//
+// No goroutines are left running once it returns, as long as cc returns when its context is cancelled.
+// (A goroutine can't be stopped from outside, so a checker that ignores its context keeps running until it
+// returns by itself - but even then CheckWebsitesContext doesn't wait for it.)
+func CheckWebsitesContext(ctx context.Context, cc ContextChecker, urls []string, options ContextOptions) (map[string]CheckResult, error) {
+	unique := dedupe(urls)
+
+	workers := len(unique)
+	if options.Concurrency > 0 && options.Concurrency < workers {
+		workers = options.Concurrency
+	}
+
+	urlChannel := make(chan string, len(unique))
+	for _, url := range unique {
+		urlChannel <- url
+	}
+	close(urlChannel)
+
+	// room for every result, so a worker never gets stuck sending one after we've stopped receiving.
+	// Like checkAll each result is sent with the url that was checked, rather than trusting the checker to fill in URL.
+	resultChannel := make(chan checkResult[CheckResult], len(unique))
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for url := range urlChannel {
+				// don't start any more checks once we've been cancelled
+				if ctx.Err() != nil {
+					return
+				}
+				resultChannel <- checkResult[CheckResult]{url, checkOne(ctx, cc, url, options.Timeout)}
+			}
+		}()
+	}
+
+	results := make(map[string]CheckResult, len(unique))
+receiving:
+	for len(results) < len(unique) {
+		select {
+		case r := <-resultChannel:
+			results[r.url] = r.result
+		case <-ctx.Done():
+			// a `break` on its own would only leave the select, the label breaks out of the for loop too
+			break receiving
+		}
+	}
+
+	// the workers stop as soon as their current check returns, which checkOne makes sure is straight away
+	wg.Wait()
+
+	// checks that finished after we stopped receiving (or while we waited) are still in the channel,
+	// they're done so they belong in the results rather than being reported as Unfinished
+	close(resultChannel)
+	for r := range resultChannel {
+		results[r.url] = r.result
+	}
+
+	unfinished := false
+	for _, url := range unique {
+		if r, ok := results[url]; !ok {
+			results[url] = CheckResult{URL: url, Err: ctx.Err(), Health: Unfinished}
+			unfinished = true
+		} else if r.Health == Unfinished {
+			unfinished = true
+		}
+	}
+	if unfinished {
+		return results, ctx.Err()
+	}
+	return results, nil
+}
+
+// checkOne runs one check with its own timeout, returning as soon as ctx or the timeout is done,
+// even if the checker hasn't returned yet
+func checkOne(ctx context.Context, cc ContextChecker, url string, timeout time.Duration) CheckResult {
+	var checkCtx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		checkCtx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		checkCtx, cancel = context.WithCancel(ctx)
+	}
+	// cancelling when we return tells a checker we've stopped waiting for it
+	defer cancel()
+
+	// buffered, so the checker's goroutine can always send its result and finish, even after we've stopped waiting
+	done := make(chan CheckResult, 1)
+	go func() {
+		done <- cc(checkCtx, url)
+	}()
+
+	var result CheckResult
+	select {
+	case result = <-done:
+		// a checker that leaves URL empty still gets results that say which url they're for
+		result.URL = url
+	case <-checkCtx.Done():
+		result = CheckResult{URL: url, Err: checkCtx.Err(), Health: Unreachable}
+	}
+
+	// if the whole run was cancelled it's not the site's fault, so it's unfinished rather than unreachable
+	if ctx.Err() != nil && result.Health == Unreachable {
+		return CheckResult{URL: url, Err: ctx.Err(), Health: Unfinished}
+	}
+	return result
+}
@@ -0,0 +1,187 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"learn-go-with-tests/clock"
+	"learn-go-with-tests/spy"
+)
+
+// hangingChecker never finishes "hang://" urls until its context is cancelled, the rest are up straight away
+func hangingChecker(ctx context.Context, url string) CheckResult {
+	if url == "hang://forever" {
+		<-ctx.Done()
+		return CheckResult{URL: url, Err: ctx.Err(), Health: Unreachable}
+	}
+	return CheckResult{URL: url, Health: Up}
+}
+
+func TestCheckWebsitesContext(t *testing.T) {
+	t.Run("every url finishes", func(t *testing.T) {
+		got, err := CheckWebsitesContext(context.Background(), hangingChecker, []string{"a", "b", "a"}, ContextOptions{})
+
+		assertNoError(t, err)
+		assertHealths(t, got, map[string]Health{"a": Up, "b": Up})
+	})
+
+	t.Run("checker that doesn't set the url", func(t *testing.T) {
+		noURL := func(ctx context.Context, url string) CheckResult {
+			return CheckResult{Health: Up}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		got, err := CheckWebsitesContext(ctx, noURL, []string{"a", "b"}, ContextOptions{})
+
+		assertNoError(t, err)
+		assertHealths(t, got, map[string]Health{"a": Up, "b": Up})
+		if got["a"].URL != "a" {
+			t.Errorf("got URL %q want %q", got["a"].URL, "a")
+		}
+	})
+
+	t.Run("overall deadline returns partial results", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		got, err := CheckWebsitesContext(ctx, hangingChecker, []string{"a", "hang://forever", "b"}, ContextOptions{})
+
+		assertContextError(t, err, context.DeadlineExceeded)
+		assertHealths(t, got, map[string]Health{"a": Up, "hang://forever": Unfinished, "b": Up})
+		assertContextError(t, got["hang://forever"].Err, context.DeadlineExceeded)
+	})
+
+	t.Run("per url timeout", func(t *testing.T) {
+		got, err := CheckWebsitesContext(context.Background(), hangingChecker, []string{"a", "hang://forever"}, ContextOptions{Timeout: 10 * time.Millisecond})
+
+		// the run as a whole finished, it was just that one site that was too slow
+		assertNoError(t, err)
+		assertHealths(t, got, map[string]Health{"a": Up, "hang://forever": Unreachable})
+		assertContextError(t, got["hang://forever"].Err, context.DeadlineExceeded)
+	})
+
+	t.Run("urls not started are unfinished", func(t *testing.T) {
+		recorder := spy.NewRecorder(nil)
+		checker := spy.FuncReturning(recorder, "check", func(url string) CheckResult { return CheckResult{URL: url, Health: Up} })
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		got, err := CheckWebsitesContext(ctx, WithContext(checker), []string{"a", "b"}, ContextOptions{Concurrency: 1})
+
+		assertContextError(t, err, context.Canceled)
+		assertHealths(t, got, map[string]Health{"a": Unfinished, "b": Unfinished})
+		spy.AssertCount(t, recorder, "check", 0)
+	})
+
+	t.Run("checks finished before cancelling are kept", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		// "a" is checked first, then "b" cancels the run - "a" finished so it mustn't be reported as Unfinished
+		checker := func(checkCtx context.Context, url string) CheckResult {
+			if url == "b" {
+				cancel()
+				<-checkCtx.Done()
+				return CheckResult{Err: checkCtx.Err(), Health: Unreachable}
+			}
+			return CheckResult{Health: Up}
+		}
+
+		got, err := CheckWebsitesContext(ctx, checker, []string{"a", "b", "c"}, ContextOptions{Concurrency: 1})
+
+		assertContextError(t, err, context.Canceled)
+		assertHealths(t, got, map[string]Health{"a": Up, "b": Unfinished, "c": Unfinished})
+	})
+
+	t.Run("doesn't wait for a checker that ignores the context", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		stuck := func(url string) CheckResult {
+			<-release
+			return CheckResult{URL: url, Health: Up}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		got, err := CheckWebsitesContext(ctx, WithContext(stuck), []string{"a"}, ContextOptions{})
+
+		assertContextError(t, err, context.DeadlineExceeded)
+		assertHealths(t, got, map[string]Health{"a": Unfinished})
+	})
+
+	t.Run("no goroutines left running after cancelling", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+		urls := []string{"hang://forever"}
+		for i := range 100 {
+			urls = append(urls, fmt.Sprintf("http://%d.example.com", i))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		CheckWebsitesContext(ctx, hangingChecker, urls, ContextOptions{Concurrency: 10})
+
+		// the checkers' own goroutines finish just after they see the cancellation, so give them a moment
+		assertGoroutinesReturnTo(t, before)
+	})
+
+	t.Run("with the http checker", func(t *testing.T) {
+		up := makeStatusServer(http.StatusOK)
+		defer up.Close()
+		stalled := make(chan struct{})
+		hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-stalled
+		}))
+		defer hanging.Close()
+		defer close(stalled)
+		checker := NewHTTPChecker(http.DefaultClient, 0, clock.Real{})
+
+		got, err := CheckWebsitesContext(context.Background(), checker.CheckContext, []string{up.URL, hanging.URL}, ContextOptions{Timeout: 20 * time.Millisecond})
+
+		assertNoError(t, err)
+		assertHealths(t, got, map[string]Health{up.URL: Up, hanging.URL: Unreachable})
+	})
+}
+
+func assertHealths(t testing.TB, got map[string]CheckResult, want map[string]Health) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("got %d results want %d: %v", len(got), len(want), got)
+	}
+	for url, health := range want {
+		if got[url].Health != health {
+			t.Errorf("%s: got %v want %v", url, got[url].Health, health)
+		}
+	}
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal("didn't expect an error but got one:", err)
+	}
+}
+
+func assertContextError(t testing.TB, got, want error) {
+	t.Helper()
+	if !errors.Is(got, want) {
+		t.Errorf("got error %v want %v", got, want)
+	}
+}
+
+func assertGoroutinesReturnTo(t testing.TB, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			t.Errorf("got %d goroutines want %d", runtime.NumGoroutine(), want)
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,72 @@
+package concurrency
+
+// A limited number of goroutines
+// CheckWebsites starts a goroutine for every url. For 3 urls that's fine, but for 50,000 it opens 50,000
+// connections at once, which floods the network (and the sites being checked).
+// CheckWebsitesWithOptions uses a "worker pool" instead: a fixed number of goroutines (workers) that each
+// take the next url from a channel, check it, and go back for another, until there are none left.
+
+type CheckOptions struct {
+	// Concurrency is the most checks that run at the same time. 0 means no limit, like CheckWebsites.
+	Concurrency int
+}
+
+// CheckWebsitesWithOptions returns the same map as CheckWebsites. Each url is only checked once,
+// however many times it appears in urls.
+func CheckWebsitesWithOptions(wc WebsiteChecker, urls []string, options CheckOptions) map[string]bool {
+	return checkAll(wc, urls, options)
+}
+
+// checkResult is like result, but for any type of check result
+type checkResult[R any] struct {
+	url    string
+	result R
+}
+
+// checkAll is the worker pool. It's generic (R is the type of result), so the same pool is used for
+// WebsiteChecker's bools and DetailedChecker's CheckResults (see checker.go).
+func checkAll[R any](check func(string) R, urls []string, options CheckOptions) map[string]R {
+	unique := dedupe(urls)
+
+	workers := len(unique)
+	if options.Concurrency > 0 && options.Concurrency < workers {
+		workers = options.Concurrency
+	}
+
+	// the urls channel has room for every url, so we can queue them all up front without waiting for a worker
+	urlChannel := make(chan string, len(unique))
+	for _, url := range unique {
+		urlChannel <- url
+	}
+	// closing tells the workers there are no more urls, so their `range` loops finish
+	close(urlChannel)
+
+	resultChannel := make(chan checkResult[R])
+	for i := 0; i < workers; i++ {
+		go func() {
+			for url := range urlChannel {
+				resultChannel <- checkResult[R]{url, check(url)}
+			}
+		}()
+	}
+
+	results := make(map[string]R, len(unique))
+	for i := 0; i < len(unique); i++ {
+		r := <-resultChannel
+		results[r.url] = r.result
+	}
+	return results
+}
+
+// dedupe keeps the first of each url, in the order they were given
+func dedupe(urls []string) []string {
+	seen := make(map[string]bool, len(urls))
+	var unique []string
+	for _, url := range urls {
+		if !seen[url] {
+			seen[url] = true
+			unique = append(unique, url)
+		}
+	}
+	return unique
+}
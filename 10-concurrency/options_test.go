@@ -0,0 +1,121 @@
+package concurrency
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"learn-go-with-tests/spy"
+)
+
+func TestCheckWebsitesWithOptions(t *testing.T) {
+	t.Run("same results as CheckWebsites", func(t *testing.T) {
+		websites := []string{
+			"http://google.com",
+			"http://blog.gypsydave5.com",
+			"waat://furhurterwe.geds",
+		}
+
+		for _, concurrency := range []int{0, 1, 2, 10} {
+			got := CheckWebsitesWithOptions(mockWebsiteChecker, websites, CheckOptions{Concurrency: concurrency})
+
+			want := CheckWebsites(mockWebsiteChecker, websites)
+			if !reflect.DeepEqual(want, got) {
+				t.Errorf("concurrency %d: wanted %v got %v", concurrency, want, got)
+			}
+		}
+	})
+
+	t.Run("checks each url once", func(t *testing.T) {
+		recorder := spy.NewRecorder(nil)
+		checker := spy.FuncReturning(recorder, "check", mockWebsiteChecker)
+		websites := []string{"http://google.com", "waat://furhurterwe.geds", "http://google.com", "http://google.com"}
+
+		got := CheckWebsitesWithOptions(checker, websites, CheckOptions{Concurrency: 2})
+
+		spy.AssertCount(t, recorder, "check", 2)
+		want := map[string]bool{"http://google.com": true, "waat://furhurterwe.geds": false}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("wanted %v got %v", want, got)
+		}
+	})
+
+	t.Run("no urls", func(t *testing.T) {
+		got := CheckWebsitesWithOptions(mockWebsiteChecker, nil, CheckOptions{Concurrency: 5})
+
+		if len(got) != 0 {
+			t.Errorf("wanted no results got %v", got)
+		}
+	})
+
+	t.Run("never more checks in flight than the limit", func(t *testing.T) {
+		const limit = 5
+		checker := NewBlockingChecker()
+		urls := make([]string, 100)
+		for i := range urls {
+			urls[i] = fmt.Sprintf("http://%d.example.com", i)
+		}
+
+		done := make(chan map[string]bool)
+		go func() {
+			done <- CheckWebsitesWithOptions(checker.Check, urls, CheckOptions{Concurrency: limit})
+		}()
+
+		// every check blocks, so once `limit` have started no more can start until one finishes
+		for i := 0; i < limit; i++ {
+			<-checker.started
+		}
+		select {
+		case <-checker.started:
+			t.Fatalf("a check started while %d were already in flight", limit)
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		// let them all finish - started has to keep being emptied as the rest start
+		close(checker.release)
+		go func() {
+			for range checker.started {
+			}
+		}()
+		results := <-done
+		close(checker.started)
+
+		if len(results) != len(urls) {
+			t.Errorf("got %d results want %d", len(results), len(urls))
+		}
+		if checker.maxInFlight != limit {
+			t.Errorf("got at most %d checks in flight want %d", checker.maxInFlight, limit)
+		}
+	})
+}
+
+// BlockingChecker is a WebsiteChecker whose checks wait until release is closed,
+// keeping track of the most that were running at once
+type BlockingChecker struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	started     chan struct{}
+	release     chan struct{}
+}
+
+func NewBlockingChecker() *BlockingChecker {
+	return &BlockingChecker{started: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (c *BlockingChecker) Check(url string) bool {
+	c.mu.Lock()
+	c.inFlight++
+	c.maxInFlight = max(c.maxInFlight, c.inFlight)
+	c.mu.Unlock()
+
+	c.started <- struct{}{}
+	<-c.release
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+	return true
+}
@@ -1,3 +1,8 @@
 module select
 
 go 1.23.2
+
+require learn-go-with-tests/clock v0.0.0
+
+// the clock package lives in this repo, rather than being downloaded
+replace learn-go-with-tests/clock => ../clock
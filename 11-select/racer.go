@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+
+	"learn-go-with-tests/clock"
 )
 
 // // function with named return
@@ -97,12 +99,18 @@ func Racer(a, b string) (winner string, error error) {
 }
 
 func ConfigurableRacer(a, b string, timeout time.Duration) (winner string, error error) {
+	return RacerWithClock(a, b, timeout, clock.Real{})
+}
+
+// The timeout test still has to wait for real time to pass. With a clock.Clock passed in (like the Sleeper in
+// the mocking chapter) the tests can use a clock.FakeClock and move time on themselves, so they don't wait at all.
+func RacerWithClock(a, b string, timeout time.Duration, clk clock.Clock) (winner string, error error) {
 	select {
 	case <-ping(a):
 		return a, nil
 	case <-ping(b):
 		return b, nil
-	case <-time.After(timeout):
+	case <-clk.After(timeout):
 		return "", fmt.Errorf("timeout out waiting for %s and %s", a, b)
 	}
 }
@@ -5,6 +5,8 @@ import (
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"learn-go-with-tests/clock"
 )
 
 // func TestRacer(t *testing.T) {
@@ -67,20 +69,40 @@ func TestRacer(t *testing.T) {
 	})
 
 	t.Run("returns an error if a server doesn't respond within 10s", func(t *testing.T) {
-		// just use the same server for this test
-		server := makeDelayedServer(25 * time.Millisecond)
-
+		server, release := makeStalledServer()
+		// release the handler before closing, as Close waits for requests to finish
 		defer server.Close()
-
-		// use ConfigurableRacer with a very short timout for this test
-		_, err := ConfigurableRacer(server.URL, server.URL, 20*time.Millisecond)
-
-		if err == nil {
-			t.Error("expected an error but didn't ge one")
+		defer release()
+
+		// ConfigurableRacer with a short timeout still had to wait for real time to pass.
+		// With a FakeClock we skip the whole ten seconds instantly, so the test doesn't wait at all.
+		fakeClock := clock.NewFakeClock(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+		errs := make(chan error)
+		go func() {
+			_, err := RacerWithClock(server.URL, server.URL, 10*time.Second, fakeClock)
+			errs <- err
+		}()
+
+		// wait for the racer to start waiting on the timeout, then skip ten seconds ahead
+		fakeClock.BlockUntil(1)
+		fakeClock.Advance(10 * time.Second)
+
+		if err := <-errs; err == nil {
+			t.Error("expected an error but didn't get one")
 		}
 	})
 }
 
+// makeStalledServer never responds until release is called
+func makeStalledServer() (server *httptest.Server, release func()) {
+	stalled := make(chan struct{})
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-stalled
+		w.WriteHeader(http.StatusOK)
+	}))
+	return server, func() { close(stalled) }
+}
+
 func makeDelayedServer(delay time.Duration) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(delay)
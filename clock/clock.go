@@ -0,0 +1,73 @@
+package clock
+
+import "time"
+
+// A shared clock
+// The mocking and select chapters each made their own fakes for time: SpySleeper, SpyTime, ConfigurableSleeper,
+// and a short timeout for ConfigurableRacer. That works, but every new use of time needs a new fake, and tests
+// that really wait (even 20ms) are slow and can be flaky on a busy machine.
+//
+// Clock has the parts of the `time` package that depend on the current time. Code takes a Clock,
+// `main` passes in Real, and tests pass in a FakeClock that only moves when the test says so.
+
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is the methods of *time.Timer. time.Timer has its channel as a field, which an interface can't have, so it's a method here.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker is the methods of *time.Ticker
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// Real is the actual time, using the `time` package
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+func (Real) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (Real) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (Real) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (Real) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+// embedding *time.Timer gives us Stop and Reset for free, we only need to add C
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
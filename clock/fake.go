@@ -0,0 +1,221 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a Clock that only moves when Advance is called, so tests don't have to wait for real time to pass.
+//
+// Timers, tickers, After and Sleep all register a "waiter" with the clock. Advance moves the time forward,
+// firing each waiter whose time has come in order, just as if that much time had really passed.
+//
+// Code under test often sleeps in another goroutine. Advancing before that goroutine has started waiting
+// would move the clock past it too early, so tests call BlockUntil first to wait for it to be waiting.
+type FakeClock struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	when time.Time
+	// period is how often a ticker fires, 0 for a timer
+	period time.Duration
+	ch     chan time.Time
+}
+
+func NewFakeClock(start time.Time) *FakeClock {
+	c := &FakeClock{now: start}
+	c.changed = sync.NewCond(&c.mu)
+	return c
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Sleep blocks until another goroutine advances the clock by d
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, waiter: &fakeWaiter{ch: make(chan time.Time, 1)}}
+	c.start(t.waiter, d)
+	return t
+}
+
+// NewTicker panics if d isn't positive, like time.NewTicker
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTicker{clock: c, waiter: &fakeWaiter{period: d, ch: make(chan time.Time, 1)}}
+	c.start(t.waiter, d)
+	return t
+}
+
+// Advance moves the clock forward by d, firing every timer and ticker that is due on the way
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	target := c.now.Add(d)
+	for {
+		next := c.nextDue(target)
+		if next == nil {
+			break
+		}
+
+		// move to the moment the waiter fires, so anything it wakes up sees the right time
+		c.now = next.when
+		fire(next)
+
+		if next.period > 0 {
+			next.when = next.when.Add(next.period)
+		} else {
+			c.remove(next)
+		}
+	}
+	c.now = target
+	c.changed.Broadcast()
+}
+
+// BlockUntil waits until there are n timers, tickers or sleeps waiting on the clock
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.changed.Wait()
+	}
+}
+
+// Waiters is the number of timers, tickers and sleeps waiting on the clock
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// start adds w to fire in d. c.mu must be held.
+func (c *FakeClock) start(w *fakeWaiter, d time.Duration) {
+	w.when = c.now.Add(d)
+	// like time.NewTimer, a timer for zero or less fires straight away
+	if d <= 0 && w.period == 0 {
+		fire(w)
+		return
+	}
+	c.waiters = append(c.waiters, w)
+	c.changed.Broadcast()
+}
+
+// nextDue is the waiter that fires first, if it's not after target. c.mu must be held.
+func (c *FakeClock) nextDue(target time.Time) *fakeWaiter {
+	var next *fakeWaiter
+	for _, w := range c.waiters {
+		if !w.when.After(target) && (next == nil || w.when.Before(next.when)) {
+			next = w
+		}
+	}
+	return next
+}
+
+// remove reports whether w was waiting. c.mu must be held.
+func (c *FakeClock) remove(w *fakeWaiter) bool {
+	for i, waiting := range c.waiters {
+		if waiting == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.changed.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+// fire sends the time without blocking - like a real ticker, if nobody has received the last tick yet this one is dropped
+func fire(w *fakeWaiter) {
+	select {
+	case w.ch <- w.when:
+	default:
+	}
+}
+
+// drain throws away a value that was sent but not received,
+// so after Stop or Reset nothing stale is received (this is how time.Timer works since Go 1.23)
+func drain(ch chan time.Time) {
+	select {
+	case <-ch:
+	default:
+	}
+}
+
+type fakeTimer struct {
+	clock  *FakeClock
+	waiter *fakeWaiter
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.waiter.ch
+}
+
+// Stop reports whether the timer was stopped before it fired
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	drain(t.waiter.ch)
+	return t.clock.remove(t.waiter)
+}
+
+// Reset reports whether the timer was still waiting
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	drain(t.waiter.ch)
+	active := t.clock.remove(t.waiter)
+	t.clock.start(t.waiter, d)
+	return active
+}
+
+type fakeTicker struct {
+	clock  *FakeClock
+	waiter *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.waiter.ch
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	drain(t.waiter.ch)
+	t.clock.remove(t.waiter)
+}
+
+func (t *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	drain(t.waiter.ch)
+	t.clock.remove(t.waiter)
+	t.waiter.period = d
+	t.clock.start(t.waiter, d)
+}
@@ -0,0 +1,170 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+// both clocks must have every method of Clock
+var (
+	_ Clock = Real{}
+	_ Clock = &FakeClock{}
+)
+
+func TestFakeClock(t *testing.T) {
+	t.Run("Now only moves when advanced", func(t *testing.T) {
+		clock := NewFakeClock(start)
+
+		assertTime(t, clock.Now(), start)
+		clock.Advance(time.Minute)
+		assertTime(t, clock.Now(), start.Add(time.Minute))
+	})
+
+	t.Run("timer fires once its time has passed", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		timer := clock.NewTimer(time.Second)
+
+		clock.Advance(999 * time.Millisecond)
+		assertNotReceived(t, timer.C())
+
+		clock.Advance(time.Millisecond)
+		assertReceived(t, timer.C(), start.Add(time.Second))
+		assertWaiters(t, clock, 0)
+	})
+
+	t.Run("zero timer fires straight away", func(t *testing.T) {
+		clock := NewFakeClock(start)
+
+		assertReceived(t, clock.After(0), start)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		timer := clock.NewTimer(time.Second)
+
+		if !timer.Stop() {
+			t.Error("Stop should report the timer was waiting")
+		}
+		clock.Advance(time.Hour)
+
+		assertNotReceived(t, timer.C())
+		if timer.Stop() {
+			t.Error("second Stop should report the timer wasn't waiting")
+		}
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		timer := clock.NewTimer(time.Second)
+
+		clock.Advance(500 * time.Millisecond)
+		timer.Reset(time.Second)
+		clock.Advance(500 * time.Millisecond)
+		assertNotReceived(t, timer.C())
+
+		clock.Advance(500 * time.Millisecond)
+		assertReceived(t, timer.C(), start.Add(1500*time.Millisecond))
+	})
+
+	t.Run("reset throws away a value that wasn't received", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		timer := clock.NewTimer(time.Second)
+		clock.Advance(time.Second)
+
+		timer.Reset(time.Second)
+
+		assertNotReceived(t, timer.C())
+	})
+
+	t.Run("ticker fires every period, dropping ticks nobody received", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		ticker := clock.NewTicker(time.Second)
+
+		clock.Advance(time.Second)
+		assertReceived(t, ticker.C(), start.Add(time.Second))
+
+		// three ticks while nobody was listening, only the first is kept, like time.Ticker
+		clock.Advance(3 * time.Second)
+		assertReceived(t, ticker.C(), start.Add(2*time.Second))
+		assertNotReceived(t, ticker.C())
+
+		ticker.Stop()
+		clock.Advance(time.Hour)
+		assertNotReceived(t, ticker.C())
+	})
+
+	t.Run("waiters fire in time order, seeing their own time", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		late := clock.After(2 * time.Second)
+		early := clock.After(time.Second)
+
+		clock.Advance(time.Hour)
+
+		assertReceived(t, early, start.Add(time.Second))
+		assertReceived(t, late, start.Add(2*time.Second))
+	})
+
+	t.Run("sleep in another goroutine", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		done := make(chan struct{})
+
+		go func() {
+			clock.Sleep(time.Minute)
+			close(done)
+		}()
+
+		// without this we might advance before the goroutine has started sleeping
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Sleep didn't wake up")
+		}
+	})
+
+	t.Run("ticker with no interval panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected a panic")
+			}
+		}()
+		NewFakeClock(start).NewTicker(0)
+	})
+}
+
+func assertTime(t testing.TB, got, want time.Time) {
+	t.Helper()
+	if !got.Equal(want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func assertReceived(t testing.TB, ch <-chan time.Time, want time.Time) {
+	t.Helper()
+	select {
+	case got := <-ch:
+		assertTime(t, got, want)
+	default:
+		t.Errorf("expected %v to have been sent", want)
+	}
+}
+
+func assertNotReceived(t testing.TB, ch <-chan time.Time) {
+	t.Helper()
+	select {
+	case got := <-ch:
+		t.Errorf("didn't expect anything to be sent, got %v", got)
+	default:
+	}
+}
+
+func assertWaiters(t testing.TB, clock *FakeClock, want int) {
+	t.Helper()
+	if got := clock.Waiters(); got != want {
+		t.Errorf("got %d waiters want %d", got, want)
+	}
+}
@@ -0,0 +1,3 @@
+module learn-go-with-tests/clock
+
+go 1.23.2
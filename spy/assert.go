@@ -0,0 +1,81 @@
+package spy
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Assertions
+// These take testing.TB, like the assertX helpers in the tests, and call t.Helper()
+// so a failure is reported at the line in the test that called them.
+
+// AssertCalls checks the names of every call, in order
+func AssertCalls(t testing.TB, r *Recorder, want ...string) {
+	t.Helper()
+	got := r.Names()
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got calls %v want %v", got, want)
+	}
+}
+
+// AssertCount checks name was called want times
+func AssertCount(t testing.TB, r *Recorder, name string, want int) {
+	t.Helper()
+	if got := r.Count(name); got != want {
+		t.Errorf("got %d calls to %q want %d", got, name, want)
+	}
+}
+
+// AssertOrder checks the names were called in this order, allowing other calls in between
+func AssertOrder(t testing.TB, r *Recorder, names ...string) {
+	t.Helper()
+	next := 0
+	for _, name := range r.Names() {
+		if next < len(names) && name == names[next] {
+			next++
+		}
+	}
+	if next < len(names) {
+		t.Errorf("calls %v don't include %v in that order", r.Names(), names)
+	}
+}
+
+// AssertInterleaved checks calls to first and second took turns, starting with first - like write, sleep, write.
+// Calls with any other name are ignored.
+func AssertInterleaved(t testing.TB, r *Recorder, first, second string) {
+	t.Helper()
+	want := first
+	for _, name := range r.Names() {
+		if name != first && name != second {
+			continue
+		}
+		if name != want {
+			t.Errorf("calls %v: %q and %q don't take turns", r.Names(), first, second)
+			return
+		}
+		if want == first {
+			want = second
+		} else {
+			want = first
+		}
+	}
+}
+
+// AssertCalledWith checks at least one call to name had exactly these arguments
+func AssertCalledWith(t testing.TB, r *Recorder, name string, args ...any) {
+	t.Helper()
+	var got [][]any
+	for _, call := range r.Calls() {
+		if call.Name != name {
+			continue
+		}
+		if reflect.DeepEqual(call.Args, args) {
+			return
+		}
+		got = append(got, call.Args)
+	}
+	t.Errorf("no call to %q with %v, got calls with %v", name, args, got)
+}
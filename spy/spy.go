@@ -0,0 +1,147 @@
+package spy
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"learn-go-with-tests/clock"
+)
+
+// A reusable spy
+// In the mocking chapter SpyCountdownOperations recorded "write" and "sleep" so a test could check the order
+// they happened in. Every new collaborator needed another hand written spy like that.
+//
+// A Recorder is one list of calls that any number of collaborators can record into - wrap a writer,
+// a sleeper and a function with the same Recorder, and the test can check how the calls to all of them
+// were interleaved. Each call keeps its arguments, and the time from a clock.Clock, so a test using a
+// clock.FakeClock can check how far apart calls were too.
+
+type Call struct {
+	// Name is the name the collaborator was wrapped with, e.g. "write"
+	Name string
+	Args []any
+	Time time.Time
+}
+
+type Recorder struct {
+	mu    sync.Mutex
+	clock clock.Clock
+	calls []Call
+}
+
+// NewRecorder uses clk for the time of each call, or clock.Real if it's nil
+func NewRecorder(clk clock.Clock) *Recorder {
+	if clk == nil {
+		clk = clock.Real{}
+	}
+	return &Recorder{clock: clk}
+}
+
+// Record adds a call. The wrappers below call it, but it can be used directly for anything they don't cover.
+// It's safe to call from more than one goroutine.
+func (r *Recorder) Record(name string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{name, args, r.clock.Now()})
+}
+
+// Calls returns a copy of every call so far, in the order they were made
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// Names returns just the name of every call, which is often all a test needs to compare
+func (r *Recorder) Names() []string {
+	var names []string
+	for _, call := range r.Calls() {
+		names = append(names, call.Name)
+	}
+	return names
+}
+
+// Count is how many times name was called
+func (r *Recorder) Count(name string) int {
+	count := 0
+	for _, call := range r.Calls() {
+		if call.Name == name {
+			count++
+		}
+	}
+	return count
+}
+
+// Reset forgets every call
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// Writer records each Write as a call with the written text as its argument, then passes it on to w.
+// If w is nil the text is only recorded.
+func (r *Recorder) Writer(name string, w io.Writer) io.Writer {
+	if w == nil {
+		w = io.Discard
+	}
+	return &writer{r, name, w}
+}
+
+type writer struct {
+	recorder *Recorder
+	name     string
+	w        io.Writer
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	w.recorder.Record(w.name, string(p))
+	return w.w.Write(p)
+}
+
+// Sleeper is anything with a Sleep method, like the Sleeper interface in the mocking chapter
+type Sleeper interface {
+	Sleep()
+}
+
+// Sleeper records each Sleep, then passes it on to s. If s is nil nothing sleeps.
+func (r *Recorder) Sleeper(name string, s Sleeper) Sleeper {
+	return &sleeper{r, name, s}
+}
+
+type sleeper struct {
+	recorder *Recorder
+	name     string
+	s        Sleeper
+}
+
+func (s *sleeper) Sleep() {
+	s.recorder.Record(s.name)
+	if s.s != nil {
+		s.s.Sleep()
+	}
+}
+
+// Func wraps a function with one argument, recording each call and its argument, e.g.
+//
+//	sleep := spy.Func(recorder, "sleep", time.Sleep)
+//
+// Methods can't have their own type parameters, so this is a function rather than a method of Recorder.
+func Func[A any](r *Recorder, name string, f func(A)) func(A) {
+	return func(a A) {
+		r.Record(name, a)
+		if f != nil {
+			f(a)
+		}
+	}
+}
+
+// FuncReturning wraps a function with one argument and a result, like the WebsiteChecker in the concurrency chapter.
+// f must not be nil, as something has to decide what to return.
+func FuncReturning[A, R any](r *Recorder, name string, f func(A) R) func(A) R {
+	return func(a A) R {
+		r.Record(name, a)
+		return f(a)
+	}
+}
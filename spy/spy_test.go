@@ -0,0 +1,161 @@
+package spy
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"learn-go-with-tests/clock"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("records calls to every collaborator in one list", func(t *testing.T) {
+		recorder := NewRecorder(nil)
+		buffer := &bytes.Buffer{}
+		out := recorder.Writer("write", buffer)
+		sleeper := recorder.Sleeper("sleep", nil)
+
+		fmt.Fprint(out, "3")
+		sleeper.Sleep()
+		fmt.Fprint(out, "Go!")
+
+		AssertCalls(t, recorder, "write", "sleep", "write")
+		AssertCalledWith(t, recorder, "write", "Go!")
+		if buffer.String() != "3Go!" {
+			t.Errorf("writes should be passed on, got %q", buffer.String())
+		}
+	})
+
+	t.Run("wrapped sleepers still sleep", func(t *testing.T) {
+		recorder := NewRecorder(nil)
+		inner := &countingSleeper{}
+
+		recorder.Sleeper("sleep", inner).Sleep()
+
+		if inner.calls != 1 {
+			t.Errorf("got %d sleeps want 1", inner.calls)
+		}
+	})
+
+	t.Run("functions record their arguments", func(t *testing.T) {
+		recorder := NewRecorder(nil)
+		sleep := Func[time.Duration](recorder, "sleep", nil)
+		check := FuncReturning(recorder, "check", func(url string) bool {
+			return !strings.Contains(url, "broken")
+		})
+
+		sleep(time.Second)
+		up := check("http://example.com")
+		down := check("http://broken.example.com")
+
+		if !up || down {
+			t.Errorf("got results %v and %v, the wrapped function's results should be returned", up, down)
+		}
+		AssertCalledWith(t, recorder, "sleep", time.Second)
+		AssertCalledWith(t, recorder, "check", "http://broken.example.com")
+		AssertCount(t, recorder, "check", 2)
+	})
+
+	t.Run("calls are timed with the clock", func(t *testing.T) {
+		fakeClock := clock.NewFakeClock(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+		recorder := NewRecorder(fakeClock)
+
+		recorder.Record("first")
+		fakeClock.Advance(time.Second)
+		recorder.Record("second")
+
+		calls := recorder.Calls()
+		if gap := calls[1].Time.Sub(calls[0].Time); gap != time.Second {
+			t.Errorf("got %v between calls want %v", gap, time.Second)
+		}
+	})
+
+	t.Run("safe to record from many goroutines", func(t *testing.T) {
+		recorder := NewRecorder(nil)
+		record := Func[int](recorder, "call", nil)
+
+		var wg sync.WaitGroup
+		for i := range 100 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				record(i)
+			}()
+		}
+		wg.Wait()
+
+		AssertCount(t, recorder, "call", 100)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		recorder := NewRecorder(nil)
+		recorder.Record("call")
+
+		recorder.Reset()
+
+		AssertCalls(t, recorder)
+	})
+}
+
+func TestAssertions(t *testing.T) {
+	recorder := NewRecorder(nil)
+	for _, name := range []string{"write", "sleep", "log", "write", "sleep", "write"} {
+		recorder.Record(name, name+" arg")
+	}
+
+	cases := []struct {
+		name   string
+		assert func(t testing.TB)
+		passes bool
+	}{
+		{"calls", func(t testing.TB) { AssertCalls(t, recorder, "write", "sleep", "log", "write", "sleep", "write") }, true},
+		{"wrong calls", func(t testing.TB) { AssertCalls(t, recorder, "write", "sleep") }, false},
+		{"count", func(t testing.TB) { AssertCount(t, recorder, "write", 3) }, true},
+		{"wrong count", func(t testing.TB) { AssertCount(t, recorder, "sleep", 3) }, false},
+		{"order with calls in between", func(t testing.TB) { AssertOrder(t, recorder, "sleep", "write", "write") }, true},
+		{"wrong order", func(t testing.TB) { AssertOrder(t, recorder, "log", "log") }, false},
+		{"interleaved, ignoring other calls", func(t testing.TB) { AssertInterleaved(t, recorder, "write", "sleep") }, true},
+		{"not interleaved", func(t testing.TB) { AssertInterleaved(t, recorder, "sleep", "write") }, false},
+		{"called with", func(t testing.TB) { AssertCalledWith(t, recorder, "log", "log arg") }, true},
+		{"not called with", func(t testing.TB) { AssertCalledWith(t, recorder, "log", "other arg") }, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			spyT := &SpyTB{}
+
+			c.assert(spyT)
+
+			if spyT.failed == c.passes {
+				t.Errorf("expected the assertion to pass: %v, but it failed with %q", c.passes, spyT.message)
+			}
+		})
+	}
+}
+
+// SpyTB is a testing.TB that remembers a failure instead of failing the real test,
+// so we can check the assertions fail when they should
+type SpyTB struct {
+	// embedding the interface gives SpyTB every method, we only implement the ones the assertions use
+	testing.TB
+	failed  bool
+	message string
+}
+
+func (s *SpyTB) Helper() {}
+
+func (s *SpyTB) Errorf(format string, args ...any) {
+	s.failed = true
+	s.message = fmt.Sprintf(format, args...)
+}
+
+type countingSleeper struct {
+	calls int
+}
+
+func (s *countingSleeper) Sleep() {
+	s.calls++
+}